@@ -4,6 +4,8 @@ import (
 	"bufio"
 	"bytes"
 	"cmp"
+	"compress/gzip"
+	"context"
 	"crypto/rand"
 	"encoding/base64"
 	"errors"
@@ -11,11 +13,16 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"regexp"
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/containerd/console"
@@ -48,8 +55,19 @@ const (
 var (
 	cacheDir  string
 	configDir string
+	cacheTTL  time.Duration
+	mdcURL    string
+	timeout   time.Duration
+	rps       float64
+	offline   bool
+	units     = dev.Celsius
+	output    = OutputText
+	cache     *devchart.Cache
 	options   *devchart.Options
 	stripMap  map[string]string
+	// stripKinds holds the stripped names of each kind, i.e. "developer"
+	// and "stock".
+	stripKinds map[string]map[string]string
 )
 
 func strip(f string) string {
@@ -77,6 +95,20 @@ func ex(err error) {
 	os.Exit(1)
 }
 
+func warn(err error) {
+	fmt.Fprintln(os.Stderr, "warning:", err)
+}
+
+// stale prints a warning and discards err if it is a devchart.StaleError or
+// a devchart.NotesError.
+func stale(err error) error {
+	if errors.As(err, &devchart.StaleError{}) || errors.As(err, &devchart.NotesError{}) {
+		warn(err)
+		return nil
+	}
+	return err
+}
+
 func getCacheDir(subs ...string) string {
 	if cacheDir == "" {
 		userCacheDir, err := os.UserCacheDir()
@@ -125,6 +157,58 @@ func aliasPath() string {
 	return p
 }
 
+// loadConfig sets the flags in set to the values in the config file,
+// one flag per line as '<name> <value>'.
+func loadConfig(set *flag.FlagSet) error {
+	p := getConfigDir("config")
+	f, err := os.Open(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	scan := bufio.NewScanner(f)
+	for scan.Scan() {
+		text := strings.TrimSpace(scan.Text())
+		if text == "" || text[0] == '#' {
+			continue
+		}
+
+		name, value, _ := strings.Cut(text, " ")
+		if set.Lookup(name) == nil {
+			return fmt.Errorf("%s: unknown option '%s'", p, name)
+		}
+		if err := set.Set(name, strings.TrimSpace(value)); err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+	}
+
+	return scan.Err()
+}
+
+func dilutionsPath() string { return getConfigDir("dilutions") }
+
+func getDilutions() (devchart.Dilutions, error) {
+	f, err := os.Open(dilutionsPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return devchart.DefaultDilutions, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	d, err := devchart.ReadDilutions(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", dilutionsPath(), err)
+	}
+
+	return devchart.DefaultDilutions.Merge(d), nil
+}
+
 func tmpFile(file string) string {
 	stamp := strconv.FormatInt(time.Now().UnixNano(), 36)
 	rnd := make([]byte, 32)
@@ -144,16 +228,18 @@ func tmpFile(file string) string {
 func parseDensity(div string) ([2]float64, error) {
 	divs := strings.SplitN(div, "/", 2)
 	var err error
-	var dens [2]float64
+	dens := [2]float64{0, 1}
 	dens[0], err = strconv.ParseFloat(divs[0], 64)
 	if err != nil {
 		return dens, fmt.Errorf("invalid decimal number: '%s': %w", div, err)
 	}
+	if len(divs) == 1 {
+		return dens, nil
+	}
 	dens[1], err = strconv.ParseFloat(divs[1], 64)
 	if err != nil {
 		return dens, fmt.Errorf("invalid decimal number: '%s': %w", div, err)
 	}
-
 	return dens, nil
 }
 
@@ -236,53 +322,170 @@ func getAliases() ([]Alias, error) {
 	return l, scan.Err()
 }
 
+func getCache() *devchart.Cache {
+	if cache == nil {
+		client, err := devchart.NewClient(mdcURL, &http.Client{Timeout: timeout})
+		ex(err)
+		client.UserAgent = "devcalc"
+		client.RPS = rps
+		cache = devchart.NewCache(client, getCacheDir("mdc"), cacheTTL)
+		cache.SetOffline(offline)
+
+		dils, err := getDilutions()
+		ex(err)
+		cache.SetDilutions(dils)
+	}
+	return cache
+}
+
+// getOptions returns the Massive Dev Chart developers and stocks and sets up
+// stripMap. A devchart.NotCachedError is returned as is, but stripMap still
+// contains the names of all other sources.
 func getOptions() (devchart.Options, error) {
 	if options == nil {
-		o, err := devchart.GetOptions(getCacheDir("mdc"))
-		if err != nil {
+		o, err := getCache().Options(context.Background())
+		err = stale(err)
+		if err != nil && !errors.As(err, &devchart.NotCachedError{}) {
 			return o, err
 		}
-		options = &o
+		notCached := err
 
 		stripMap = make(map[string]string)
+		stripKinds = map[string]map[string]string{
+			"developer": make(map[string]string),
+			"stock":     make(map[string]string),
+		}
 		for _, k := range o.Developers {
 			stripMap[strip(k)] = k
+			stripKinds["developer"][strip(k)] = k
 		}
 		for _, k := range o.Stocks {
 			stripMap[strip(k)] = k
+			stripKinds["stock"][strip(k)] = k
 		}
+
+		srcs, err := localSources()
+		if err != nil {
+			return o, err
+		}
+		for _, src := range srcs {
+			devs, _ := src.Developers(context.Background())
+			stocks, _ := src.Stocks(context.Background())
+			for _, k := range append(devs, stocks...) {
+				if _, ok := stripMap[strip(k)]; !ok {
+					stripMap[strip(k)] = k
+				}
+			}
+			for kind, names := range map[string][]string{"developer": devs, "stock": stocks} {
+				for _, k := range names {
+					if _, ok := stripKinds[kind][strip(k)]; !ok {
+						stripKinds[kind][strip(k)] = k
+					}
+				}
+			}
+		}
+
+		if notCached != nil {
+			return o, notCached
+		}
+		options = &o
 	}
 	return *options, nil
 }
 
 func printOptions(strs []string) {
+	if output != OutputText {
+		opts := make([]optionRecord, 0, len(strs))
+		for _, e := range strs {
+			opts = append(opts, optionRecord{ID: strip(e), Name: e})
+		}
+		ex(writeOptions(os.Stdout, opts))
+		return
+	}
+
 	for _, e := range strs {
 		fmt.Println(strip(e))
 	}
 }
 
+// filterEntries returns the entries of developer chem from all sources that
+// list it.
 func filterEntries(chem, stock, iso, ratio string) ([]devchart.Entry, error) {
-	entries, err := devchart.Get(getCacheDir("mdc"), chem)
+	srcs, err := getSources()
 	if err != nil {
 		return nil, err
 	}
 
-	stockQuery := wcGen(stock)
+	var entries []devchart.Entry
+	var notCached error
+	found := false
+	for _, src := range srcs {
+		devs, err := src.Developers(context.Background())
+		if err == nil && !slices.ContainsFunc(devs, func(d string) bool { return strip(d) == strip(chem) }) {
+			continue
+		}
+
+		es, err := src.Developer(context.Background(), chem)
+		if errors.As(err, &devchart.NotExistsError{}) {
+			continue
+		}
+		if errors.As(err, &devchart.NotCachedError{}) {
+			notCached = err
+			continue
+		}
+		if err = stale(err); err != nil {
+			return nil, err
+		}
+		found = true
+		entries = append(entries, es...)
+	}
+	if notCached != nil {
+		if !found {
+			return nil, notCached
+		}
+		warn(notCached)
+	}
+	if !found {
+		return nil, fmt.Errorf("no such developer: '%s'", chem)
+	}
+
+	return filter(mergeLocal(entries), stock, iso, ratio)
+}
+
+// filter returns the entries that match the stock pattern (see
+// compilePattern), iso filter (see parseISOFilter) and ratio, empty strings
+// match all entries.
+func filter(entries []devchart.Entry, stock, iso, ratio string) ([]devchart.Entry, error) {
+	var stockRE *regexp.Regexp
+	if stock != "" {
+		var err error
+		if stockRE, err = compilePattern(stock); err != nil {
+			return nil, err
+		}
+	}
+
+	var isoF isoFilter
+	if iso != "" {
+		var err error
+		if isoF, err = parseISOFilter(iso); err != nil {
+			return nil, err
+		}
+	}
+
 	filtered := make([]devchart.Entry, 0, len(entries))
 	for _, e := range entries {
-		if iso != "" && e.ISO != iso {
+		if isoF != nil && !isoF.match(e.ISO) {
 			continue
 		}
 
 		if ratio != "" {
-			eratio := dev.ScaleString(dev.ScaleParts(e.Dilution))
+			eratio := dev.ScaleParts(e.Dilution).String()
 			if eratio != ratio {
 				continue
 			}
 		}
 
-		name := strip(e.Name)
-		if stock != "" && !wcMatch(stockQuery, name) {
+		if stockRE != nil && !matchName(stockRE, e.Name) {
 			continue
 		}
 
@@ -292,130 +495,207 @@ func filterEntries(chem, stock, iso, ratio string) ([]devchart.Entry, error) {
 	return filtered, nil
 }
 
-func printEntries(entries []devchart.Entry, format Format) {
-	slices.SortFunc(entries, func(a, b devchart.Entry) int {
-		if n := cmp.Compare(a.Developer, b.Developer); n != 0 {
-			return n
-		}
+// printGrouped prints entries grouped by developer.
+func printGrouped(entries []devchart.Entry, format Format) {
+	if output != OutputText {
+		printEntries(entries, format)
+		return
+	}
 
-		if n := cmp.Compare(a.Name, b.Name); n != 0 {
-			return n
+	groups := make(map[string][]devchart.Entry)
+	devs := make([]string, 0)
+	for _, e := range entries {
+		if _, ok := groups[e.Developer]; !ok {
+			devs = append(devs, e.Developer)
 		}
+		groups[e.Developer] = append(groups[e.Developer], e)
+	}
 
-		isoA, err := strconv.Atoi(a.ISO)
-		if err != nil {
-			return 1
-		}
-		isoB, err := strconv.Atoi(b.ISO)
-		if err != nil {
-			return -1
-		}
+	slices.Sort(devs)
+	for _, dev := range devs {
+		fmt.Println(strip(dev))
+		printEntries(groups[dev], format)
+		fmt.Println("")
+	}
+}
 
-		if n := cmp.Compare(isoA, isoB); n != 0 {
-			return n
-		}
+func fmtDuration(dur time.Duration) string {
+	s := int(dur.Seconds())
+	buf := bytes.NewBuffer(make([]byte, 0, 8))
+	if s >= 3600 {
+		h := s / 3600
+		s -= h * 3600
 
-		if n := cmp.Compare(a.Dilution, b.Dilution); n != 0 {
-			return n
-		}
+		buf.WriteString(strconv.Itoa(h))
+		buf.WriteString("h")
+	}
 
-		if n := cmp.Compare(a.Temp, b.Temp); n != 0 {
-			return n
-		}
+	if s >= 60 {
+		m := s / 60
+		s -= m * 60
+		buf.WriteString(strconv.Itoa(m))
+		buf.WriteString("m")
+	}
 
-		return 0
-	})
+	if s != 0 {
+		buf.WriteString(strconv.Itoa(s))
+		buf.WriteString("s")
+	}
 
-	r := func(dur time.Duration) string {
-		s := int(dur.Seconds())
-		buf := bytes.NewBuffer(make([]byte, 8))
-		//return fmt.Sprintf("%dh
-		if s >= 3600 {
-			h := s / 3600
-			s -= h * 3600
+	return buf.String()
+}
 
-			buf.WriteString(strconv.Itoa(h))
-			buf.WriteString("h")
-		}
+// fmtTime formats t as e.g. 7m30s, 8m-10m or 6m+4m, times that could not be
+// parsed are printed as listed.
+func fmtTime(t devchart.Time) string {
+	if t.IsZero() {
+		return t.Raw
+	}
 
-		if s >= 60 {
-			m := s / 60
-			s -= m * 60
-			buf.WriteString(strconv.Itoa(m))
-			buf.WriteString("m")
+	stages := make([]string, 0, len(t.Stages))
+	for _, s := range t.Stages {
+		str := fmtDuration(s.Min)
+		if s.Max != s.Min {
+			str += "-" + fmtDuration(s.Max)
 		}
+		stages = append(stages, str)
+	}
 
-		if s != 0 {
-			buf.WriteString(strconv.Itoa(s))
-			buf.WriteString("s")
-		}
+	return strings.Join(stages, "+")
+}
+
+// fmtDilution formats the dilution of e as a ratio, prefixed with its
+// dilution code if it had one (e.g. B (1+31)).
+func fmtDilution(e devchart.Entry) string {
+	ratio := e.Dilution
+	if r := dev.ScaleParts(e.Dilution); r.Valid() {
+		ratio = r.String()
+	}
+	if e.DilutionCode == "" {
+		return ratio
+	}
+
+	return fmt.Sprintf("%s (%s)", e.DilutionCode, ratio)
+}
+
+// parseRatio parses a ratio given on the command line, printing how a ratio
+// in total parts notation is read.
+func parseRatio(s string) (dev.Ratio, error) {
+	r, err := dev.ParseRatio(s)
+	if err != nil {
+		return r, err
+	}
+	if r.Notation == dev.NotationTotal {
+		fmt.Fprintf(os.Stderr, "%s is read as %s part in %s parts in total: %s\n", s, fmtFloat(r.Chem()[0]), fmtFloat(r.Total()), r)
+	}
+
+	return r, nil
+}
+
+// fmtTemp formats c degrees Celsius in the configured units.
+func fmtTemp(c float64) string {
+	if c == 0 {
+		return "?" + units.String()
+	}
+	return fmt.Sprintf("%.1f%s", units.FromCelsius(c), units)
+}
 
-		return buf.String()
+func printEntries(entries []devchart.Entry, format Format) {
+	sortEntries(entries)
+	if output != OutputText {
+		ex(writeEntries(os.Stdout, entries))
+		return
 	}
 
 	for _, e := range entries {
-		ratio := dev.ScaleString(dev.ScaleParts(e.Dilution))
-		notes := make([]string, 1, 1+len(e.Notes))
-		for _, n := range e.Notes {
-			notes = append(notes, fmt.Sprintf("        %s", n))
-		}
+		fmt.Println(fmtEntry(e, format))
+	}
+}
 
-		if len(notes) == 1 {
-			notes = notes[:0]
-		}
+func fmtEntry(e devchart.Entry, format Format) string {
+	ratio := fmtDilution(e)
+	notes := make([]string, 1, 1+len(e.Notes))
+	for _, n := range e.Notes {
+		notes = append(notes, fmt.Sprintf("        %s", n))
+	}
+
+	if len(notes) == 1 {
+		notes = notes[:0]
+	}
 
-		dur := make([]string, 0, 3)
+	dur := make([]string, 0, 3)
 
-		if format&Format135 != 0 && e.T135 != 0 {
-			dur = append(dur, fmt.Sprintf("[135: %s]", r(e.T135)))
-		}
-		if format&Format120 != 0 && e.T120 != 0 {
-			dur = append(dur, fmt.Sprintf("[120: %s]", r(e.T120)))
-		}
-		if format&FormatSheet != 0 && e.TSheet != 0 {
-			dur = append(dur, fmt.Sprintf("[sheet: %s]", r(e.TSheet)))
+	fmtT := func(t devchart.Time) string {
+		if a, ok := adjust(e, t); ok {
+			return fmt.Sprintf("%s, %s at %s", fmtTime(t), fmtTime(a), fmtTemp(atTemp))
 		}
+		return fmtTime(t)
+	}
 
-		fmt.Printf(
-			"%6s) %s %s %.1fC %s%s\n",
-			e.ISO,
-			strip(e.Name),
-			ratio,
-			e.Temp,
-			strings.Join(dur, " "),
-			strings.Join(notes, "\n"),
-		)
+	if format&Format135 != 0 && e.T135.Raw != "" {
+		dur = append(dur, fmt.Sprintf("[135: %s]", fmtT(e.T135)))
 	}
+	if format&Format120 != 0 && e.T120.Raw != "" {
+		dur = append(dur, fmt.Sprintf("[120: %s]", fmtT(e.T120)))
+	}
+	if format&FormatSheet != 0 && e.TSheet.Raw != "" {
+		dur = append(dur, fmt.Sprintf("[sheet: %s]", fmtT(e.TSheet)))
+	}
+
+	if e.Source != "" && e.Source != devchart.SourceMDC {
+		dur = append(dur, fmt.Sprintf("(%s)", e.Source))
+	}
+
+	return fmt.Sprintf(
+		"%6s) %s %s %s %s%s",
+		e.ISO,
+		strip(e.Name),
+		ratio,
+		fmtTemp(e.Temp),
+		strings.Join(dur, " "),
+		strings.Join(notes, "\n"),
+	)
 }
 
-func wcGen(str string) []string {
-	return strings.Split(strings.ToLower(str), "*")
+func sortEntries(entries []devchart.Entry) {
+	slices.SortFunc(entries, compareEntries)
 }
 
-func wcMatch(query []string, target string) bool {
-	lc := strings.ToLower(target)
-	for i, p := range query {
-		method := strings.Contains
-		if i == 0 {
-			method = strings.HasPrefix
-		}
-		if i == len(query)-1 {
-			method = strings.HasSuffix
-		}
+func compareEntries(a, b devchart.Entry) int {
+	if n := cmp.Compare(a.Developer, b.Developer); n != 0 {
+		return n
+	}
 
-		if p == "" {
-			continue
-		}
+	if n := cmp.Compare(a.Name, b.Name); n != 0 {
+		return n
+	}
 
-		if len(query) == 1 {
-			return lc == p
+	isoA, okA := devchart.ParseISO(a.ISO)
+	isoB, okB := devchart.ParseISO(b.ISO)
+	switch {
+	case !okA && !okB:
+		if n := cmp.Compare(a.ISO, b.ISO); n != 0 {
+			return n
 		}
-
-		if !method(lc, p) {
-			return false
+	case !okA:
+		return 1
+	case !okB:
+		return -1
+	default:
+		if n := cmp.Compare(isoA, isoB); n != 0 {
+			return n
 		}
 	}
-	return true
+
+	if n := cmp.Compare(a.Dilution, b.Dilution); n != 0 {
+		return n
+	}
+
+	if n := cmp.Compare(a.Temp, b.Temp); n != 0 {
+		return n
+	}
+
+	return 0
 }
 
 type Size struct{ X, Y int }
@@ -433,17 +713,30 @@ func main() {
 	fr := flags.NewRoot(os.Stdout)
 
 	fr.Define(func(set *flag.FlagSet) func(io.Writer) {
+		set.DurationVar(&cacheTTL, "ttl", time.Hour*24*30, "refetch cached Massive Dev Chart data older than this, 0 to never expire")
+		set.StringVar(&mdcURL, "url", devchart.DefaultURL, "Massive Dev Chart url, e.g. a local mirror")
+		set.DurationVar(&timeout, "timeout", time.Second*30, "timeout of Massive Dev Chart requests")
+		set.Float64Var(&rps, "rps", 2, "maximum number of Massive Dev Chart requests per second, 0 for no limit")
+		set.Var(&units, "units", "temperature units, C or F")
+		set.Var(&output, "output", "output format: text, json, csv or markdown")
+		set.BoolVar(&offline, "offline", false, "only use cached Massive Dev Chart data, regardless of its age, never fetch anything")
 		return func(w io.Writer) {
 			fmt.Fprintln(w, "Usage:")
+			fmt.Fprintln(w, "  ", set.Name(), "[flags] <command>")
 			fmt.Fprintln(w, "  ", set.Name(), "calc:  Calculate developing volumes")
 			fmt.Fprintln(w, "  ", set.Name(), "alias: Alias developers and optionally store densities")
 			fmt.Fprintln(w, "  ", set.Name(), "mdc:   Massive Dev Chart operations")
+			fmt.Fprintln(w, "  ", set.Name(), "chart: Manage your own chart entries")
 			fmt.Fprintln(w, "  ", set.Name(), "timer: Run a developing timer")
+			fmt.Fprintln(w, "Flags:")
+			set.PrintDefaults()
+			fmt.Fprintln(w, "Defaults can be set in", getConfigDir("config"), "one flag per line (e.g. 'units F')")
 		}
 	}).Handler(func(set *flags.Set, args []string) error {
 		set.Usage(1)
 		return nil
 	})
+	ex(loadConfig(flag.CommandLine))
 
 	fr.Add("timer").Define(func(set *flag.FlagSet) func(io.Writer) {
 		return func(w io.Writer) {
@@ -685,7 +978,12 @@ func main() {
 			fmt.Fprintln(w, "Usage:")
 			fmt.Fprintln(w, "  ", set.Name(), "list:   Get a listing of developers or stocks")
 			fmt.Fprintln(w, "  ", set.Name(), "get     Get the Massive Dev Chart table (with notes) of a specific developer")
+			fmt.Fprintln(w, "  ", set.Name(), "film:   Get the Massive Dev Chart entries of all developers for a specific stock")
 			fmt.Fprintln(w, "  ", set.Name(), "getall: Get all Massive Dev Chart tables, effectively caching all data")
+			fmt.Fprintln(w, "  ", set.Name(), "refresh: Refetch cached Massive Dev Chart data")
+			fmt.Fprintln(w, "  ", set.Name(), "diff:   Show what changed in the Massive Dev Chart tables after a refresh")
+			fmt.Fprintln(w, "  ", set.Name(), "export: Export all cached Massive Dev Chart data to a file")
+			fmt.Fprintln(w, "  ", set.Name(), "import: Import Massive Dev Chart data from a file created by export")
 		}
 	}).Handler(func(set *flags.Set, args []string) error {
 		cmdMDC.Usage(1)
@@ -731,29 +1029,60 @@ func main() {
 		return nil
 	})
 
+	var getTemp string
 	cmdMDCGet := cmdMDC.Add("get").Define(func(set *flag.FlagSet) func(io.Writer) {
+		set.StringVar(&getTemp, "temp", "", "also show times adjusted to this temperature (e.g. 23, 23C or 74F), without unit in -units of the root command")
 		return func(w io.Writer) {
 			fmt.Fprintln(w, "Get development times for the given developer and stock")
 			fmt.Fprintln(w, "Usage:")
-			fmt.Fprintln(w, set.Name(), "<developer>", "[stock]", "[iso]")
-			fmt.Fprintln(w, "  <developer>  required, use `mdc list developers` to get a listing.")
-			fmt.Fprintln(w, "  [stock]      optional, use `mdc list stocks`     to get a listing. supports * for wildcard matching.")
-			fmt.Fprintln(w, "  [iso]        optional, only show entries with the specified iso.")
+			fmt.Fprintln(w, set.Name(), "[flags]", "<developer>", "[stock]", "[iso]")
+			fmt.Fprintln(w, "  <developer>  required, use `mdc list developers` to get a listing. supports glob patterns and re:<regexp>.")
+			fmt.Fprintln(w, "  [stock]      optional, use `mdc list stocks`     to get a listing. supports glob patterns and re:<regexp>.")
+			fmt.Fprintln(w, "  [iso]        optional, only show entries with this iso, e.g. 400, >=800, 400-1600 or 400,800.")
+			fmt.Fprintln(w, "Flags:")
+			set.PrintDefaults()
 		}
 	}).Handler(func(set *flags.Set, args []string) error {
 		if len(args) == 0 || len(args) > 3 {
 			set.Usage(1)
 		}
 
-		chem, _ := unstrip(args[0])
+		if err := setAtTemp(getTemp); err != nil {
+			return err
+		}
+
 		var stock, iso string
 		if len(args) > 1 {
-			stock = strip(args[1])
+			stock = args[1]
 		}
 		if len(args) > 2 {
 			iso = args[2]
 		}
 
+		if isPattern(args[0]) {
+			devs, err := matchDevelopers(args[0])
+			if err != nil {
+				return err
+			}
+
+			var entries []devchart.Entry
+			for _, chem := range devs {
+				filtered, err := filterEntries(chem, stock, iso, "")
+				if err != nil {
+					return err
+				}
+				entries = append(entries, filtered...)
+			}
+			printGrouped(entries, Format135|Format120|FormatSheet)
+
+			return nil
+		}
+
+		chem, err := resolve("developer", args[0])
+		if err != nil {
+			return err
+		}
+
 		filtered, err := filterEntries(chem, stock, iso, "")
 		if err != nil {
 			return err
@@ -764,97 +1093,736 @@ func main() {
 		return nil
 	})
 
-	cmdMDC.Add("getall").Define(func(set *flag.FlagSet) func(io.Writer) {
+	cmdMDC.Add("estimate").Define(func(set *flag.FlagSet) func(io.Writer) {
 		return func(w io.Writer) {
-			fmt.Fprintln(w, "Get all Massive Dev Chart tables, effectively caching all data")
+			fmt.Fprintln(w, "Estimate development times at an iso that is not listed")
+			fmt.Fprintln(w, "Times are interpolated between the two nearest listed speeds of the same stock,")
+			fmt.Fprintln(w, "dilution and temperature, or extrapolated from the two nearest if iso lies outside them.")
+			fmt.Fprintln(w, "Entries that list iso are shown unchanged.")
+			fmt.Fprintln(w, "Usage:")
+			fmt.Fprintln(w, set.Name(), "<developer>", "<stock>", "<iso>", "[dilution]")
+			fmt.Fprintln(w, "  <developer>  required, use `mdc list developers` to get a listing.")
+			fmt.Fprintln(w, "  <stock>      required, use `mdc list stocks`     to get a listing. supports glob patterns and re:<regexp>.")
+			fmt.Fprintln(w, "  <iso>        required, the iso to estimate times for.")
+			fmt.Fprintln(w, "  [dilution]   optional, only estimate for this dilution.")
 		}
 	}).Handler(func(set *flags.Set, args []string) error {
-		o, err := getOptions()
+		if len(args) < 3 || len(args) > 4 {
+			set.Usage(1)
+		}
+
+		chem, err := resolve("developer", args[0])
 		if err != nil {
 			return err
 		}
-		for _, dev := range o.Developers {
-			entries, err := devchart.Get(getCacheDir("mdc"), dev)
-			if errors.As(err, &devchart.NotExistsError{}) {
-				continue
-			}
+		iso, ok := devchart.ParseISO(args[2])
+		if !ok || iso <= 0 {
+			return fmt.Errorf("invalid iso: '%s'", args[2])
+		}
+		var ratio string
+		if len(args) > 3 {
+			r, err := parseRatio(args[3])
 			if err != nil {
 				return err
 			}
-			fmt.Println(strip(dev))
-			printEntries(entries, Format135|Format120|FormatSheet)
-			fmt.Println("")
+			ratio = r.String()
 		}
-		return nil
-	})
 
-	fr.Add("alias").Define(func(set *flag.FlagSet) func(io.Writer) {
-		return func(w io.Writer) {
-			fmt.Fprintln(w, "Alias a developer to a different name and optionally store its density")
-			fmt.Fprintln(w, "Aliases are stored in ", aliasPath()) // can cause an exit
-			fmt.Fprintln(w, "(e.g. alias adox.adonal rodinal 280/200 to set the density of adox.adonal to 1.4g/ml and use it as a rodinal alias")
-			fmt.Fprintln(w, "Usage:")
-			fmt.Fprintln(w, set.Name(), "<alias>", "<developer>", "[density]")
-			fmt.Fprintln(w, "  <alias>      required")
-			fmt.Fprintln(w, "  <developer>  required, use `mdc list developers` to get a listing")
-			fmt.Fprintln(w, "  [density]    optional, the density, can be a decimal number or a fraction (e.g.: 0.7 or 300.5/1000)")
-		}
-	}).Handler(func(set *flags.Set, args []string) error {
-		if len(args) < 2 || len(args) > 3 {
-			set.Usage(1)
-			return nil
+		entries, err := filterEntries(chem, args[1], "", "")
+		if err != nil {
+			return err
 		}
-
-		if _, ok := unstrip(args[1]); !ok {
-			return fmt.Errorf("no such developer: '%s'", args[1])
+		if ratio != "" {
+			all := entries
+			if entries, err = filter(all, "", "", ratio); err != nil {
+				return err
+			}
+			if len(entries) == 0 && len(all) != 0 {
+				return fmt.Errorf("no entries at dilution %s", ratio)
+			}
 		}
 
-		var dens [2]float64
-		if len(args) == 3 {
-			var err error
-			dens, err = parseDensity(args[2])
+		var listed []devchart.Entry
+		var estimates []devchart.Estimate
+		for _, group := range estimateGroups(entries, isoGroup) {
+			est, err := devchart.EstimateISO(group, iso)
+			var l devchart.ListedError
+			if errors.As(err, &l) {
+				warn(fmt.Errorf("%s %s: iso %s is listed, showing the listed times", strip(group[0].Name), fmtDilution(group[0]), args[2]))
+				listed = append(listed, l.Entries...)
+				continue
+			}
 			if err != nil {
-				return err
+				continue
+			}
+			if est.Extrapolated {
+				warn(fmt.Errorf("%s %s: iso %s is outside the listed speeds, times are extrapolated", strip(est.Name), fmtDilution(est.Entry), est.ISO))
 			}
+			estimates = append(estimates, est)
+		}
+		if len(estimates) == 0 && len(listed) == 0 {
+			return fmt.Errorf("not enough entries to estimate times for iso %s", args[2])
 		}
 
-		aliases, err := getAliases()
-		ex(err)
+		printEstimates(listed, estimates, Format135|Format120|FormatSheet)
 
-		aliases = append(aliases, Alias{args[0], args[1], dens})
-		return setAliases(aliases)
+		return nil
 	})
 
-	fr.Add("calc").Define(func(set *flag.FlagSet) func(io.Writer) {
+	var filmFetch bool
+	cmdMDC.Add("film").Define(func(set *flag.FlagSet) func(io.Writer) {
+		set.BoolVar(&filmFetch, "fetch", false, "fetch the entries of all developers for <stock> instead of only searching cached developers")
 		return func(w io.Writer) {
-			fmt.Fprintln(w, "Calculate developing volumes")
+			fmt.Fprintln(w, "Get development times of all developers for the given stock")
+			fmt.Fprintln(w, "Only cached developers are searched, see `mdc getall` or -fetch.")
 			fmt.Fprintln(w, "Usage:")
-			fmt.Fprintln(w, set.Name(), "<developer>", "<ratio>", "<volume>", "[stock]", "[iso]")
-			fmt.Fprintln(w, "  <developer>  required, use `mdc list developers` to get a listing.")
-			fmt.Fprintln(w, "                         can also be any of your aliases with a stored density for mixing by weight.")
-			fmt.Fprintln(w, "  <ratio>      required, the dilution to use.")
-			fmt.Fprintln(w, "  <volume>     required, the total developing volume (ml).")
-			fmt.Fprintf(w, "  [stock]      optional, also print developing information. (see: %s)\n", cmdMDCGet.Name())
-			fmt.Fprintln(w, "  [iso]        optional, only show entries with the specified iso.")
+			fmt.Fprintln(w, set.Name(), "[flags]", "<stock>", "[iso]")
+			fmt.Fprintln(w, "  <stock>      required, use `mdc list stocks` to get a listing. supports glob patterns and re:<regexp> without -fetch.")
+			fmt.Fprintln(w, "  [iso]        optional, only show entries with this iso, e.g. 400, >=800, 400-1600 or 400,800.")
+			fmt.Fprintln(w, "Flags:")
+			set.PrintDefaults()
 		}
 	}).Handler(func(set *flags.Set, args []string) error {
-		if len(args) < 3 || len(args) > 5 {
+		if len(args) == 0 || len(args) > 2 {
 			set.Usage(1)
-			return nil
 		}
 
-		aliases := make(map[string]Alias)
-		{
-			_a, err := getAliases()
+		stock := args[0]
+		var iso string
+		if len(args) > 1 {
+			iso = args[1]
+		}
+
+		var entries []devchart.Entry
+		if filmFetch {
+			film, err := resolve("stock", stock)
 			if err != nil {
 				return err
 			}
-			for _, a := range _a {
-				aliases[a.Alias] = a
+			stock = rePrefix + "^" + regexp.QuoteMeta(film) + "$"
+			entries, err = getCache().Film(context.Background(), film)
+			if err = stale(err); err != nil {
+				return err
 			}
-		}
-
-		chem := args[0]
+		} else {
+			o, err := getOptions()
+			if err != nil {
+				return err
+			}
+			for _, dev := range o.Developers {
+				es, _ := getCache().Peek(dev)
+				entries = append(entries, es...)
+			}
+		}
+
+		srcs, err := localSources()
+		if err != nil {
+			return err
+		}
+		stockRE, err := compilePattern(stock)
+		if err != nil {
+			return err
+		}
+		for _, src := range srcs {
+			stocks, err := src.Stocks(context.Background())
+			if err != nil {
+				return err
+			}
+			for _, s := range stocks {
+				if !matchName(stockRE, s) {
+					continue
+				}
+				es, err := src.Film(context.Background(), s)
+				if err != nil {
+					return err
+				}
+				entries = append(entries, es...)
+			}
+		}
+		entries = mergeLocal(entries)
+
+		entries, err = filter(entries, stock, iso, "")
+		if err != nil {
+			return err
+		}
+		printGrouped(entries, Format135|Format120|FormatSheet)
+
+		return nil
+	})
+
+	cmdMDC.Add("grep").Define(func(set *flag.FlagSet) func(io.Writer) {
+		return func(w io.Writer) {
+			fmt.Fprintln(w, "Search the notes of all cached entries")
+			fmt.Fprintln(w, "Only cached developers are searched, see `mdc getall`.")
+			fmt.Fprintln(w, "Usage:")
+			fmt.Fprintln(w, set.Name(), "<pattern>", "[developer]", "[stock]")
+			fmt.Fprintln(w, "  <pattern>    required, case-insensitive regular expression.")
+			fmt.Fprintln(w, "  [developer]  optional, only search entries of this developer. supports glob patterns and re:<regexp>.")
+			fmt.Fprintln(w, "  [stock]      optional, only search entries of this stock. supports glob patterns and re:<regexp>.")
+		}
+	}).Handler(func(set *flags.Set, args []string) error {
+		if len(args) == 0 || len(args) > 3 {
+			set.Usage(1)
+		}
+
+		re, err := regexp.Compile("(?i)" + args[0])
+		if err != nil {
+			return fmt.Errorf("invalid pattern '%s': %w", args[0], err)
+		}
+
+		var devRE *regexp.Regexp
+		if len(args) > 1 {
+			if devRE, err = compilePattern(args[1]); err != nil {
+				return err
+			}
+		}
+		var stock string
+		if len(args) > 2 {
+			stock = args[2]
+		}
+
+		entries, err := cachedEntries(devRE)
+		if err != nil {
+			return err
+		}
+		entries, err = filter(entries, stock, "", "")
+		if err != nil {
+			return err
+		}
+
+		printGrouped(grepNotes(entries, re, output == OutputText), Format135|Format120|FormatSheet)
+
+		return nil
+	})
+
+	var getallWorkers int
+	cmdMDC.Add("getall").Define(func(set *flag.FlagSet) func(io.Writer) {
+		set.IntVar(&getallWorkers, "workers", 4, "number of developers to fetch concurrently")
+		return func(w io.Writer) {
+			fmt.Fprintln(w, "Get all Massive Dev Chart tables, effectively caching all data")
+			fmt.Fprintln(w, "Already cached developers are not refetched, so an interrupted run can")
+			fmt.Fprintln(w, "simply be resumed by running it again.")
+			fmt.Fprintln(w, "Can not be used with -offline.")
+			fmt.Fprintln(w, "Flags:")
+			set.PrintDefaults()
+		}
+	}).Handler(func(set *flags.Set, args []string) error {
+		if offline {
+			return errors.New("getall fetches every developer and can not be used with -offline")
+		}
+
+		o, err := getOptions()
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		results := make([][]devchart.Entry, len(o.Developers))
+		prog := newProgress(os.Stderr, len(o.Developers))
+		jobs := make(chan int)
+		var failed atomic.Int32
+		var wg sync.WaitGroup
+		for w := 0; w < max(getallWorkers, 1); w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range jobs {
+					dev := o.Developers[i]
+					entries, err := getCache().Developer(ctx, dev)
+					if errors.As(err, &devchart.NotExistsError{}) {
+						prog.Step(strip(dev))
+						continue
+					}
+					if err != nil && ctx.Err() != nil {
+						continue
+					}
+					if err != nil {
+						prog.Warn(err)
+						if !errors.As(err, &devchart.StaleError{}) && !errors.As(err, &devchart.NotesError{}) {
+							failed.Add(1)
+							prog.Step(strip(dev))
+							continue
+						}
+					}
+					results[i] = entries
+					prog.Step(strip(dev))
+				}
+			}()
+		}
+
+	feed:
+		for i := range o.Developers {
+			select {
+			case <-ctx.Done():
+				break feed
+			case jobs <- i:
+			}
+		}
+		close(jobs)
+		wg.Wait()
+		prog.Done()
+
+		all := make([]devchart.Entry, 0)
+		for _, entries := range results {
+			all = append(all, entries...)
+		}
+		printGrouped(all, Format135|Format120|FormatSheet)
+
+		if ctx.Err() != nil {
+			return errors.New("interrupted, run getall again to resume")
+		}
+		if n := failed.Load(); n != 0 {
+			return fmt.Errorf("failed to get %d developers, run getall again to retry", n)
+		}
+
+		return nil
+	})
+
+	cmdMDC.Add("refresh").Define(func(set *flag.FlagSet) func(io.Writer) {
+		return func(w io.Writer) {
+			fmt.Fprintln(w, "Refetch cached Massive Dev Chart data, regardless of its age")
+			fmt.Fprintln(w, "Usage:")
+			fmt.Fprintln(w, set.Name(), "[developer...]")
+			fmt.Fprintln(w, "  [developer]  optional, only refresh these developers.")
+			fmt.Fprintln(w, "               if omitted the list of developers and stocks and all cached developers are refreshed.")
+		}
+	}).Handler(func(set *flags.Set, args []string) error {
+		devs := make([]string, 0, len(args))
+		for _, arg := range args {
+			chem, _ := unstrip(strip(arg))
+			devs = append(devs, chem)
+		}
+
+		if len(devs) == 0 {
+			o, err := getCache().RefreshOptions(context.Background())
+			if err = stale(err); err != nil {
+				return err
+			}
+			for _, dev := range o.Developers {
+				if _, ok := getCache().Cached(dev); ok {
+					devs = append(devs, dev)
+				}
+			}
+		}
+
+		refreshed := make([]refreshRecord, 0, len(devs))
+		for _, dev := range devs {
+			entries, err := getCache().Refresh(context.Background(), dev)
+			if err = stale(err); err != nil {
+				return err
+			}
+			if output != OutputText {
+				refreshed = append(refreshed, refreshRecord{dev, len(entries)})
+				continue
+			}
+			fmt.Printf("%s: %d entries\n", strip(dev), len(entries))
+		}
+
+		if output != OutputText {
+			return writeRefreshed(os.Stdout, refreshed)
+		}
+
+		return nil
+	})
+
+	cmdMDC.Add("diff").Define(func(set *flag.FlagSet) func(io.Writer) {
+		return func(w io.Writer) {
+			fmt.Fprintln(w, "Show entries that were added (+), removed (-) or changed (~) by the last refresh that changed them")
+			fmt.Fprintln(w, "Usage:")
+			fmt.Fprintln(w, set.Name(), "[developer]")
+			fmt.Fprintln(w, "  [developer]  optional, only show changes of this developer.")
+		}
+	}).Handler(func(set *flags.Set, args []string) error {
+		if len(args) > 1 {
+			set.Usage(1)
+		}
+
+		var devs []string
+		if len(args) == 1 {
+			chem, _ := unstrip(strip(args[0]))
+			devs = append(devs, chem)
+		} else {
+			o, err := getOptions()
+			if err != nil {
+				return err
+			}
+			devs = o.Developers
+		}
+
+		var diffs []diffRecord
+		for _, dev := range devs {
+			prev, prevFetched, ok := getCache().Previous(dev)
+			if !ok {
+				if len(args) == 1 {
+					return fmt.Errorf("no previous version of '%s' cached", strip(dev))
+				}
+				continue
+			}
+			cur, ok := getCache().Peek(dev)
+			if !ok {
+				continue
+			}
+			changes := devchart.Diff(prev, cur)
+			if len(changes) == 0 {
+				continue
+			}
+			sortChanges(changes)
+
+			fetched, _ := getCache().Cached(dev)
+			if output != OutputText {
+				diffs = append(diffs, diffRecord{dev, prevFetched, fetched, changes})
+				continue
+			}
+			fmt.Printf(
+				"%s (%s -> %s)\n",
+				strip(dev),
+				prevFetched.Format(time.DateOnly),
+				fetched.Format(time.DateOnly),
+			)
+			printChanges(changes)
+			fmt.Println("")
+		}
+
+		if output != OutputText {
+			return writeChanges(os.Stdout, diffs)
+		}
+
+		return nil
+	})
+
+	cmdMDC.Add("export").Define(func(set *flag.FlagSet) func(io.Writer) {
+		return func(w io.Writer) {
+			fmt.Fprintln(w, "Export all cached Massive Dev Chart data (developers, stocks and notes)")
+			fmt.Fprintln(w, "Usage:")
+			fmt.Fprintln(w, set.Name(), "<file>")
+			fmt.Fprintln(w, "  <file>  required, the file to write to, - for stdout. gzip compressed if it ends in .gz")
+		}
+	}).Handler(func(set *flags.Set, args []string) error {
+		if len(args) != 1 {
+			set.Usage(1)
+		}
+
+		if args[0] == "-" {
+			return getCache().Export(os.Stdout)
+		}
+
+		tmp := tmpFile(args[0])
+		f, err := os.Create(tmp)
+		if err != nil {
+			return err
+		}
+
+		var w io.WriteCloser = f
+		if strings.HasSuffix(args[0], ".gz") {
+			w = gzip.NewWriter(f)
+		}
+
+		err = getCache().Export(w)
+		if w != f {
+			if cerr := w.Close(); err == nil {
+				err = cerr
+			}
+		}
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(tmp)
+			return err
+		}
+
+		return os.Rename(tmp, args[0])
+	})
+
+	cmdMDC.Add("import").Define(func(set *flag.FlagSet) func(io.Writer) {
+		return func(w io.Writer) {
+			fmt.Fprintln(w, "Import Massive Dev Chart data created by export, more recently cached data is kept")
+			fmt.Fprintln(w, "Usage:")
+			fmt.Fprintln(w, set.Name(), "<file>")
+			fmt.Fprintln(w, "  <file>  required, the file to read from, - for stdin. may be gzip compressed")
+		}
+	}).Handler(func(set *flags.Set, args []string) error {
+		if len(args) != 1 {
+			set.Usage(1)
+		}
+
+		var f io.Reader = os.Stdin
+		if args[0] != "-" {
+			file, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			f = file
+		}
+
+		r := bufio.NewReader(f)
+		magic, _ := r.Peek(2)
+		if bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+			gz, err := gzip.NewReader(r)
+			if err != nil {
+				return err
+			}
+			defer gz.Close()
+			return getCache().Import(gz)
+		}
+
+		return getCache().Import(r)
+	})
+
+	var cmdChart *flags.Set
+	cmdChart = fr.Add("chart").Define(func(set *flag.FlagSet) func(io.Writer) {
+		return func(w io.Writer) {
+			fmt.Fprintln(w, "Manage your own chart entries, these are shown alongside (and replace)")
+			fmt.Fprintln(w, "Massive Dev Chart entries with the same stock, developer, dilution and iso.")
+			fmt.Fprintln(w, "Entries are stored in", chartPath())
+			fmt.Fprintln(w, "Usage:")
+			fmt.Fprintln(w, "  ", set.Name(), "list:   List entries")
+			fmt.Fprintln(w, "  ", set.Name(), "add:    Add an entry")
+			fmt.Fprintln(w, "  ", set.Name(), "edit:   Edit an entry")
+			fmt.Fprintln(w, "  ", set.Name(), "remove: Remove an entry")
+		}
+	}).Handler(func(set *flags.Set, args []string) error {
+		cmdChart.Usage(1)
+		return nil
+	})
+
+	cmdChart.Add("list").Define(func(set *flag.FlagSet) func(io.Writer) {
+		return func(w io.Writer) {
+			fmt.Fprintln(w, "List your chart entries and their ids")
+			fmt.Fprintln(w, "Usage:")
+			fmt.Fprintln(w, set.Name(), "[developer]", "[stock]")
+			fmt.Fprintln(w, "  [developer]  optional, only list entries of this developer. supports glob patterns and re:<regexp>.")
+			fmt.Fprintln(w, "  [stock]      optional, only list entries of this stock. supports glob patterns and re:<regexp>.")
+		}
+	}).Handler(func(set *flags.Set, args []string) error {
+		if len(args) > 2 {
+			set.Usage(1)
+		}
+
+		c, err := getChart()
+		if err != nil {
+			return err
+		}
+
+		var devRE *regexp.Regexp
+		if len(args) > 0 {
+			if devRE, err = compilePattern(args[0]); err != nil {
+				return err
+			}
+		}
+		var stock string
+		if len(args) > 1 {
+			stock = args[1]
+		}
+		ids := make([]int, 0)
+		entries := make([]devchart.Entry, 0)
+		for i, e := range c.Entries {
+			if devRE != nil && !matchName(devRE, e.Developer) {
+				continue
+			}
+			if l, err := filter([]devchart.Entry{e}, stock, "", ""); err != nil {
+				return err
+			} else if len(l) == 0 {
+				continue
+			}
+			if output != OutputText {
+				ids, entries = append(ids, i+1), append(entries, e)
+				continue
+			}
+			fmt.Printf("%3d: %s %s\n", i+1, strip(e.Developer), fmtEntry(e, Format135|Format120|FormatSheet))
+		}
+
+		if output != OutputText {
+			return writeChartEntries(os.Stdout, ids, entries)
+		}
+
+		return nil
+	})
+
+	var addFlags entryFlags
+	cmdChart.Add("add").Define(func(set *flag.FlagSet) func(io.Writer) {
+		addFlags.define(set, false)
+		return func(w io.Writer) {
+			fmt.Fprintln(w, "Add an entry to your chart")
+			fmt.Fprintln(w, "Usage:")
+			fmt.Fprintln(w, set.Name(), "[flags]", "<developer>", "<stock>", "<iso>", "<dilution>")
+			fmt.Fprintln(w, "  <developer>  required, use `mdc list developers` to get a listing or use your own.")
+			fmt.Fprintln(w, "  <stock>      required, use `mdc list stocks` to get a listing or use your own.")
+			fmt.Fprintln(w, "  <iso>        required")
+			fmt.Fprintln(w, "  <dilution>   required, e.g. 1+50")
+			fmt.Fprintln(w, "Flags:")
+			set.PrintDefaults()
+		}
+	}).Handler(func(set *flags.Set, args []string) error {
+		if len(args) != 4 {
+			set.Usage(1)
+		}
+
+		c, err := getChart()
+		if err != nil {
+			return err
+		}
+
+		var e devchart.Entry
+		e.Developer = chartName("developer", args[0])
+		e.Name = chartName("stock", args[1])
+		e.ISO = args[2]
+		e.Dilution = args[3]
+		e.Source = devchart.SourceLocal
+		if err := addFlags.apply(set.FlagSet(), &e); err != nil {
+			return err
+		}
+
+		c.Entries = append(c.Entries, e)
+		if err := setChart(c); err != nil {
+			return err
+		}
+		if output != OutputText {
+			return writeChartEntries(os.Stdout, []int{len(c.Entries)}, []devchart.Entry{e})
+		}
+		fmt.Printf("%3d: %s %s\n", len(c.Entries), strip(e.Developer), fmtEntry(e, Format135|Format120|FormatSheet))
+
+		return nil
+	})
+
+	var editFlags entryFlags
+	cmdChart.Add("edit").Define(func(set *flag.FlagSet) func(io.Writer) {
+		editFlags.define(set, true)
+		return func(w io.Writer) {
+			fmt.Fprintln(w, "Edit an entry of your chart, only the given flags are changed")
+			fmt.Fprintln(w, "Usage:")
+			fmt.Fprintln(w, set.Name(), "[flags]", "<id>")
+			fmt.Fprintln(w, "  <id>  required, see `chart list`")
+			fmt.Fprintln(w, "Flags:")
+			set.PrintDefaults()
+		}
+	}).Handler(func(set *flags.Set, args []string) error {
+		if len(args) != 1 {
+			set.Usage(1)
+		}
+
+		c, ix, err := chartEntry(args[0])
+		if err != nil {
+			return err
+		}
+
+		e := c.Entries[ix]
+		if err := editFlags.apply(set.FlagSet(), &e); err != nil {
+			return err
+		}
+
+		c.Entries[ix] = e
+		if err := setChart(c); err != nil {
+			return err
+		}
+		if output != OutputText {
+			return writeChartEntries(os.Stdout, []int{ix + 1}, []devchart.Entry{e})
+		}
+		fmt.Printf("%3d: %s %s\n", ix+1, strip(e.Developer), fmtEntry(e, Format135|Format120|FormatSheet))
+
+		return nil
+	})
+
+	cmdChart.Add("remove").Define(func(set *flag.FlagSet) func(io.Writer) {
+		return func(w io.Writer) {
+			fmt.Fprintln(w, "Remove an entry from your chart")
+			fmt.Fprintln(w, "Usage:")
+			fmt.Fprintln(w, set.Name(), "<id>")
+			fmt.Fprintln(w, "  <id>  required, see `chart list`")
+		}
+	}).Handler(func(set *flags.Set, args []string) error {
+		if len(args) != 1 {
+			set.Usage(1)
+		}
+
+		c, ix, err := chartEntry(args[0])
+		if err != nil {
+			return err
+		}
+
+		c.Entries = append(c.Entries[:ix], c.Entries[ix+1:]...)
+		return setChart(c)
+	})
+
+	fr.Add("alias").Define(func(set *flag.FlagSet) func(io.Writer) {
+		return func(w io.Writer) {
+			fmt.Fprintln(w, "Alias a developer to a different name and optionally store its density")
+			fmt.Fprintln(w, "Aliases are stored in ", aliasPath()) // can cause an exit
+			fmt.Fprintln(w, "(e.g. alias adox.adonal rodinal 280/200 to set the density of adox.adonal to 1.4g/ml and use it as a rodinal alias")
+			fmt.Fprintln(w, "Usage:")
+			fmt.Fprintln(w, set.Name(), "<alias>", "<developer>", "[density]")
+			fmt.Fprintln(w, "  <alias>      required")
+			fmt.Fprintln(w, "  <developer>  required, use `mdc list developers` to get a listing")
+			fmt.Fprintln(w, "  [density]    optional, the density, can be a decimal number or a fraction (e.g.: 0.7 or 300.5/1000)")
+		}
+	}).Handler(func(set *flags.Set, args []string) error {
+		if len(args) < 2 || len(args) > 3 {
+			set.Usage(1)
+			return nil
+		}
+
+		chem, err := resolve("developer", args[1])
+		if err != nil {
+			return err
+		}
+
+		var dens [2]float64
+		if len(args) == 3 {
+			dens, err = parseDensity(args[2])
+			if err != nil {
+				return err
+			}
+		}
+
+		aliases, err := getAliases()
+		ex(err)
+
+		aliases = append(aliases, Alias{args[0], strip(chem), dens})
+		return setAliases(aliases)
+	})
+
+	var calcTemp string
+	fr.Add("calc").Define(func(set *flag.FlagSet) func(io.Writer) {
+		set.StringVar(&calcTemp, "temp", "", "also show times adjusted to this temperature (e.g. 23, 23C or 74F), without unit in -units of the root command")
+		return func(w io.Writer) {
+			fmt.Fprintln(w, "Calculate developing volumes")
+			fmt.Fprintln(w, "Usage:")
+			fmt.Fprintln(w, set.Name(), "[flags]", "<developer>", "<ratio>", "<volume>", "[stock]", "[iso]")
+			fmt.Fprintln(w, "  <developer>  required, use `mdc list developers` to get a listing.")
+			fmt.Fprintln(w, "                         can also be any of your aliases with a stored density for mixing by weight.")
+			fmt.Fprintln(w, "  <ratio>      required, the dilution to use.")
+			fmt.Fprintln(w, "  <volume>     required, the total developing volume (ml).")
+			fmt.Fprintf(w, "  [stock]      optional, also print developing information. (see: %s)\n", cmdMDCGet.Name())
+			fmt.Fprintln(w, "                         times are estimated from other dilutions for each stock and iso that does not list <ratio>.")
+			fmt.Fprintln(w, "  [iso]        optional, only show entries with this iso, e.g. 400, >=800, 400-1600 or 400,800.")
+			fmt.Fprintln(w, "Flags:")
+			set.PrintDefaults()
+		}
+	}).Handler(func(set *flags.Set, args []string) error {
+		if len(args) < 3 || len(args) > 5 {
+			set.Usage(1)
+			return nil
+		}
+
+		if err := setAtTemp(calcTemp); err != nil {
+			return err
+		}
+
+		aliases := make(map[string]Alias)
+		{
+			_a, err := getAliases()
+			if err != nil {
+				return err
+			}
+			for _, a := range _a {
+				aliases[a.Alias] = a
+			}
+		}
+
+		chem := args[0]
 		ratio := args[1]
 		vol, err := strconv.ParseFloat(args[2], 64)
 		if err != nil {
@@ -870,22 +1838,53 @@ func main() {
 		}
 		alias := aliases[chem]
 
-		fmt.Println(dev.Calc(dev.NewChem(alias.Density(), dev.ScaleRatio(ratio)), vol))
-		if stock == "" {
-			return nil
+		r, err := parseRatio(ratio)
+		if err != nil {
+			return err
+		}
+		result := dev.Calc(dev.NewRatioChem(r, alias.Density()), vol)
+
+		var filtered []devchart.Entry
+		var estimates []devchart.Estimate
+		if stock != "" {
+			if alias.Dev != "" {
+				chem = alias.Dev
+			}
+			chem, err = resolve("developer", chem)
+			if err != nil {
+				return err
+			}
+			qratio := r.String()
+
+			all, err := filterEntries(chem, stock, iso, "")
+			if err != nil {
+				return err
+			}
+			filtered, err = filter(all, "", "", qratio)
+			if err != nil {
+				return err
+			}
+			sortEntries(filtered)
+			estimates = estimateDilution(all, r)
 		}
 
-		if alias.Dev != "" {
-			chem = alias.Dev
+		if output != OutputText {
+			return writeCalc(os.Stdout, result, filtered, estimates, stock != "")
 		}
-		chem, _ = unstrip(chem)
-		qratio := dev.ScaleString(dev.ScaleParts(ratio))
 
-		filtered, err := filterEntries(chem, stock, iso, qratio)
-		if err != nil {
-			return err
+		fmt.Println(result)
+		if stock != "" {
+			printEntries(filtered, Format135|Format120|FormatSheet)
+		}
+		if len(estimates) != 0 {
+			if len(filtered) != 0 {
+				fmt.Println()
+				fmt.Printf("estimated for the stocks and speeds that do not list %s, from the nearest listed dilutions:\n", r)
+			} else {
+				fmt.Printf("no entries for %s, estimated from the nearest listed dilutions:\n", r)
+			}
+			printEstimates(nil, estimates, Format135|Format120|FormatSheet)
 		}
-		printEntries(filtered, Format135|Format120|FormatSheet)
 
 		return nil
 	})
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/frizinak/devcalc/dev"
+	"github.com/frizinak/devcalc/devchart"
+)
+
+var (
+	chart      *devchart.Chart
+	datasheets []devchart.Source
+)
+
+func chartPath() string { return getConfigDir("chart.json") }
+
+func getChart() (devchart.Chart, error) {
+	if chart != nil {
+		return *chart, nil
+	}
+
+	f, err := os.Open(chartPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			chart = &devchart.Chart{}
+			return *chart, nil
+		}
+		return devchart.Chart{}, err
+	}
+	defer f.Close()
+
+	c, err := devchart.ReadChart(f, devchart.SourceLocal)
+	if err != nil {
+		return c, fmt.Errorf("%s: %w", chartPath(), err)
+	}
+	chart = &c
+
+	return c, nil
+}
+
+func setChart(c devchart.Chart) error {
+	path := chartPath()
+	if err := os.MkdirAll(getConfigDir(), 0755); err != nil {
+		return err
+	}
+
+	tmp := tmpFile(path)
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	if err := c.Write(f); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	chart = &c
+	return os.Rename(tmp, path)
+}
+
+func datasheetsPath() string { return getConfigDir("datasheets") }
+
+// getDatasheets returns a source for each datasheet in datasheetsPath, named
+// after its file.
+func getDatasheets() ([]devchart.Source, error) {
+	if datasheets != nil {
+		return datasheets, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(datasheetsPath(), "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	l := make([]devchart.Source, 0, len(files))
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+
+		name := strings.TrimSuffix(filepath.Base(file), ".json")
+		c, err := devchart.ReadChart(f, name)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		l = append(l, c)
+	}
+	datasheets = l
+
+	return l, nil
+}
+
+// localSources returns all sources apart from the Massive Dev Chart, the
+// local chart last.
+func localSources() ([]devchart.Source, error) {
+	l, err := getDatasheets()
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := getChart()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(slices.Clip(l), c), nil
+}
+
+// getSources returns all sources, the Massive Dev Chart first.
+func getSources() ([]devchart.Source, error) {
+	l, err := localSources()
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]devchart.Source{getCache()}, l...), nil
+}
+
+func overrideKey(e devchart.Entry) string {
+	ratio := e.Dilution
+	if r := dev.ScaleParts(e.Dilution); r.Valid() {
+		ratio = r.String()
+	}
+
+	return strings.Join([]string{strip(e.Name), strip(e.Developer), ratio, e.ISO}, "\x00")
+}
+
+// mergeLocal removes the entries that are replaced by an entry of the local
+// chart with the same film, developer, dilution and iso.
+func mergeLocal(entries []devchart.Entry) []devchart.Entry {
+	override := make(map[string]struct{})
+	for _, e := range entries {
+		if e.Source == devchart.SourceLocal {
+			override[overrideKey(e)] = struct{}{}
+		}
+	}
+
+	merged := make([]devchart.Entry, 0, len(entries))
+	for _, e := range entries {
+		if _, ok := override[overrideKey(e)]; ok && e.Source != devchart.SourceLocal {
+			continue
+		}
+		merged = append(merged, e)
+	}
+
+	return merged
+}
+
+// chartEntry returns the chart and the index of the entry with the given id.
+func chartEntry(id string) (devchart.Chart, int, error) {
+	c, err := getChart()
+	if err != nil {
+		return c, 0, err
+	}
+
+	n, err := strconv.Atoi(id)
+	if err != nil || n < 1 || n > len(c.Entries) {
+		return c, 0, fmt.Errorf("no such chart entry: '%s'", id)
+	}
+
+	return c, n - 1, nil
+}
+
+// chartName returns the Massive Dev Chart name of a developer or stock
+// (kind) given on the command line, or name as typed if it does not name one.
+func chartName(kind, name string) string {
+	_, _ = getOptions()
+	if v, ok := stripKinds[kind][strip(name)]; ok {
+		return v
+	}
+
+	return name
+}
+
+type stringsFlag []string
+
+func (s *stringsFlag) String() string { return strings.Join(*s, ", ") }
+
+func (s *stringsFlag) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+// entryFlags are the flags used to set the fields of a local chart entry.
+type entryFlags struct {
+	developer, stock, iso, dilution string
+	temp, t135, t120, tsheet        string
+	notes                           stringsFlag
+}
+
+func (f *entryFlags) define(set *flag.FlagSet, withKeys bool) {
+	if withKeys {
+		set.StringVar(&f.developer, "developer", "", "developer")
+		set.StringVar(&f.stock, "stock", "", "film stock")
+		set.StringVar(&f.iso, "iso", "", "iso")
+		set.StringVar(&f.dilution, "dilution", "", "dilution (e.g. 1+50)")
+	}
+	set.StringVar(&f.temp, "temp", "", "temperature (e.g. 20, 20C or 68F), without unit in -units of the root command")
+	set.StringVar(&f.t135, "135", "", "135 time in minutes or as duration (e.g. 9, 8-10, 6+4 or 7m30s)")
+	set.StringVar(&f.t120, "120", "", "120 time, see -135")
+	set.StringVar(&f.tsheet, "sheet", "", "sheet time, see -135")
+	set.Var(&f.notes, "note", "note, can be given multiple times")
+}
+
+// apply sets the fields of e for all flags in set that were given.
+func (f *entryFlags) apply(set *flag.FlagSet, e *devchart.Entry) error {
+	var err error
+	set.Visit(func(fl *flag.Flag) {
+		if err != nil {
+			return
+		}
+
+		switch fl.Name {
+		case "developer":
+			e.Developer = chartName("developer", f.developer)
+		case "stock":
+			e.Name = chartName("stock", f.stock)
+		case "iso":
+			e.ISO = f.iso
+		case "dilution":
+			e.Dilution = f.dilution
+		case "temp":
+			e.Temp, err = dev.ParseTemp(f.temp, units)
+		case "135":
+			e.T135, err = parseChartTime(f.t135)
+		case "120":
+			e.T120, err = parseChartTime(f.t120)
+		case "sheet":
+			e.TSheet, err = parseChartTime(f.tsheet)
+		case "note":
+			e.Notes = []string(f.notes)
+		}
+	})
+
+	return err
+}
+
+// parseChartTime parses a time in Massive Dev Chart notation (decimal minutes)
+// or a duration (e.g. 7m30s).
+func parseChartTime(s string) (devchart.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		s = strconv.FormatFloat(d.Minutes(), 'f', -1, 64)
+	}
+
+	t := devchart.ParseTime(s)
+	if t.IsZero() && s != "" {
+		return t, fmt.Errorf("invalid time: '%s'", s)
+	}
+
+	return t, nil
+}
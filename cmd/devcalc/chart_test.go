@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/frizinak/devcalc/devchart"
+)
+
+func TestChartName(t *testing.T) {
+	defer func() { options, stripMap, stripKinds = nil, nil, nil }()
+	setOptions(devchart.Options{
+		Developers: []string{"Rodinal", "Kodak HC-110"},
+		Stocks:     []string{"Kentmere 400", "Ilford HP5+"},
+	})
+
+	tests := []struct {
+		kind, name, want string
+	}{
+		{"developer", "rodinal", "Rodinal"},
+		{"developer", "kodakhc110", "Kodak HC-110"},
+		{"developer", "Caffenol-CH", "Caffenol-CH"},
+		{"stock", "kentmere 400", "Kentmere 400"},
+		{"stock", "Foma 100", "Foma 100"},
+		// only names of the given kind are resolved.
+		{"stock", "rodinal", "rodinal"},
+	}
+
+	for _, tt := range tests {
+		if got := chartName(tt.kind, tt.name); got != tt.want {
+			t.Errorf("chartName(%q, %q) = %q, want %q", tt.kind, tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/frizinak/devcalc/dev"
+	"github.com/frizinak/devcalc/devchart"
+)
+
+var (
+	compensations dev.Compensations
+	// atTemp is the temperature in degrees Celsius times are adjusted to,
+	// only used if adjustTemp is set.
+	atTemp     float64
+	adjustTemp bool
+)
+
+func compensationsPath() string { return getConfigDir("compensation") }
+
+func getCompensations() (dev.Compensations, error) {
+	if compensations != nil {
+		return compensations, nil
+	}
+
+	f, err := os.Open(compensationsPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			compensations = dev.DefaultCompensations
+			return compensations, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	c, err := dev.ReadCompensations(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", compensationsPath(), err)
+	}
+	compensations = dev.DefaultCompensations.Merge(c)
+
+	return compensations, nil
+}
+
+// setAtTemp parses the -temp flag of a command and sets atTemp.
+func setAtTemp(temp string) error {
+	if temp == "" {
+		return nil
+	}
+
+	t, err := dev.ParseTemp(temp, units)
+	if err != nil {
+		return err
+	}
+	if _, err := getCompensations(); err != nil {
+		return err
+	}
+	atTemp = t
+	adjustTemp = true
+
+	return nil
+}
+
+// adjust returns t of e adjusted to atTemp, ok is false if times are not
+// adjusted or e is listed at atTemp.
+func adjust(e devchart.Entry, t devchart.Time) (devchart.Time, bool) {
+	from := e.Temp
+	if from == 0 {
+		from = dev.StandardTemp
+	}
+	if !adjustTemp || from == atTemp || t.IsZero() {
+		return t, false
+	}
+
+	return t.Scale(compensations.For(e.Developer).Factor(from, atTemp)), true
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/frizinak/devcalc/dev"
+	"github.com/frizinak/devcalc/devchart"
+)
+
+func TestAdjust(t *testing.T) {
+	defer func(dir string) {
+		configDir, compensations = dir, nil
+		atTemp, adjustTemp = 0, false
+	}(configDir)
+	configDir = t.TempDir()
+
+	e := devchart.Entry{Developer: "Rodinal", Temp: 20, T135: devchart.ParseTime("10")}
+	if _, ok := adjust(e, e.T135); ok {
+		t.Fatal("adjusted without -temp")
+	}
+
+	for _, temp := range []string{"0", "0C", "32F", "24"} {
+		atTemp, adjustTemp = 0, false
+		if err := setAtTemp(temp); err != nil {
+			t.Fatal(err)
+		}
+		want, _ := dev.ParseTemp(temp, units)
+		a, ok := adjust(e, e.T135)
+		if !ok {
+			t.Errorf("-temp %s: not adjusted", temp)
+			continue
+		}
+		longer := a.Total().Min > e.T135.Total().Min
+		if longer != (want < e.Temp) {
+			t.Errorf("-temp %s: adjusted %s to %s", temp, e.T135.Raw, a.Raw)
+		}
+	}
+
+	atTemp, adjustTemp = 0, false
+	if err := setAtTemp("20"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := adjust(e, e.T135); ok {
+		t.Error("adjusted an entry listed at -temp")
+	}
+}
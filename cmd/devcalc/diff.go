@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/frizinak/devcalc/devchart"
+)
+
+func changeEntry(c devchart.Change) devchart.Entry {
+	if c.New != nil {
+		return *c.New
+	}
+	return *c.Old
+}
+
+func sortChanges(changes []devchart.Change) {
+	slices.SortFunc(changes, func(a, b devchart.Change) int {
+		return compareEntries(changeEntry(a), changeEntry(b))
+	})
+}
+
+// printChanges prints added (+), removed (-) and changed (~) entries, the
+// latter followed by the fields that changed.
+func printChanges(changes []devchart.Change) {
+	const all = Format135 | Format120 | FormatSheet
+	for _, c := range changes {
+		switch {
+		case c.Old == nil:
+			fmt.Println("+", fmtEntry(*c.New, all))
+		case c.New == nil:
+			fmt.Println("-", fmtEntry(*c.Old, all))
+		default:
+			n := *c.New
+			n.Notes = nil
+			fmt.Println("~", fmtEntry(n, all))
+			for _, l := range entryChanges(*c.Old, *c.New) {
+				fmt.Printf("          %s\n", l)
+			}
+		}
+	}
+}
+
+func entryChanges(o, n devchart.Entry) []string {
+	l := make([]string, 0)
+	field := func(name, old, new string) {
+		if old != new {
+			l = append(l, fmt.Sprintf("%s: %s -> %s", name, old, new))
+		}
+	}
+
+	orNone := func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		return s
+	}
+
+	field("dilution", fmtDilution(o), fmtDilution(n))
+	field("temp", fmtTemp(o.Temp), fmtTemp(n.Temp))
+	field("135", orNone(fmtTime(o.T135)), orNone(fmtTime(n.T135)))
+	field("120", orNone(fmtTime(o.T120)), orNone(fmtTime(n.T120)))
+	field("sheet", orNone(fmtTime(o.TSheet)), orNone(fmtTime(n.TSheet)))
+
+	for _, note := range o.Notes {
+		if !slices.Contains(n.Notes, note) {
+			l = append(l, "note removed: "+note)
+		}
+	}
+	for _, note := range n.Notes {
+		if !slices.Contains(o.Notes, note) {
+			l = append(l, "note added: "+note)
+		}
+	}
+
+	if len(l) == 0 {
+		field("notes url", orNone(o.NotesURL), orNone(n.NotesURL))
+	}
+
+	return l
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/frizinak/devcalc/dev"
+	"github.com/frizinak/devcalc/devchart"
+)
+
+// isoGroup is the estimateGroups key of entries that devchart.EstimateISO can
+// estimate from: film, developer, dilution and temperature.
+func isoGroup(e devchart.Entry) string {
+	return strings.Join([]string{
+		strip(e.Name),
+		strip(e.Developer),
+		fmtDilution(e),
+		strconv.FormatFloat(e.Temp, 'f', -1, 64),
+	}, "\x00")
+}
+
+// dilutionGroup is the estimateGroups key of entries that
+// devchart.EstimateDilution can estimate from: film, developer, iso and
+// temperature.
+func dilutionGroup(e devchart.Entry) string {
+	iso := e.ISO
+	if n, ok := devchart.ParseISO(e.ISO); ok {
+		iso = strconv.FormatFloat(n, 'f', -1, 64)
+	}
+
+	return strings.Join([]string{
+		strip(e.Name),
+		strip(e.Developer),
+		iso,
+		strconv.FormatFloat(e.Temp, 'f', -1, 64),
+	}, "\x00")
+}
+
+// estimateGroups groups entries by key, ordered by key.
+func estimateGroups(entries []devchart.Entry, key func(devchart.Entry) string) [][]devchart.Entry {
+	groups := make(map[string][]devchart.Entry)
+	keys := make([]string, 0)
+	for _, e := range entries {
+		k := key(e)
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], e)
+	}
+	slices.Sort(keys)
+
+	l := make([][]devchart.Entry, len(keys))
+	for i, k := range keys {
+		l[i] = groups[k]
+	}
+
+	return l
+}
+
+// printEstimates prints the listed entries unchanged, followed by each
+// estimate and the entries it was derived from.
+func printEstimates(listed []devchart.Entry, estimates []devchart.Estimate, format Format) {
+	listed = slices.Clone(listed)
+	sortEntries(listed)
+	if output != OutputText {
+		ex(writeEstimates(os.Stdout, listed, estimates))
+		return
+	}
+
+	for _, e := range listed {
+		fmt.Println(fmtEntry(e, format))
+	}
+	for i, est := range estimates {
+		if i != 0 || len(listed) != 0 {
+			fmt.Println()
+		}
+		fmt.Println(fmtEntry(est.Entry, format))
+		fmt.Println("  from:")
+		from := slices.Clone(est.From)
+		sortEntries(from)
+		for _, e := range from {
+			fmt.Println(fmtEntry(e, format))
+		}
+	}
+}
+
+// estimateDilution estimates the times at ratio for each film and iso of
+// entries that lists other dilutions, but not ratio.
+func estimateDilution(entries []devchart.Entry, ratio dev.Ratio) []devchart.Estimate {
+	var estimates []devchart.Estimate
+	for _, group := range estimateGroups(entries, dilutionGroup) {
+		est, err := devchart.EstimateDilution(group, ratio)
+		if err != nil {
+			continue
+		}
+		if est.Extrapolated {
+			warn(fmt.Errorf("%s %s: %s is outside the listed dilutions, times are extrapolated", strip(est.Name), est.ISO, ratio))
+		}
+		estimates = append(estimates, est)
+	}
+
+	return estimates
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// maxSuggestions is the number of suggestions listed for an unknown name.
+const maxSuggestions = 3
+
+// distance returns the optimal string alignment distance between a and b,
+// i.e. the Levenshtein distance where swapping two adjacent characters counts
+// as a single edit.
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(ra)][len(rb)]
+}
+
+type match struct {
+	key  string
+	dist int
+}
+
+// fuzzy returns the keys of names within maxDist of k, closest first.
+func fuzzy(names map[string]string, k string, maxDist int) []match {
+	l := make([]match, 0)
+	for key := range names {
+		if d := distance(k, key); d <= maxDist {
+			l = append(l, match{key, d})
+		}
+	}
+	slices.SortFunc(l, func(a, b match) int {
+		if n := cmp.Compare(a.dist, b.dist); n != 0 {
+			return n
+		}
+		return strings.Compare(a.key, b.key)
+	})
+
+	return l
+}
+
+// resolve returns the full name of the developer or stock (kind) name.
+// A name that is not known is replaced by the closest known name of the same
+// kind if there is a single one that is close enough, otherwise the error
+// lists the closest names.
+func resolve(kind, name string) (string, error) {
+	_, _ = getOptions()
+	names := stripKinds[kind]
+	k := strip(name)
+	if v, ok := names[k]; ok {
+		return v, nil
+	}
+	if options == nil {
+		// the options could not be loaded, nothing to match against.
+		return name, nil
+	}
+
+	matches := fuzzy(names, k, max(2, len(k)/2))
+	auto := max(1, len(k)/4)
+	if len(matches) != 0 && matches[0].dist <= auto &&
+		(len(matches) == 1 || matches[1].dist > matches[0].dist) {
+		fmt.Fprintf(os.Stderr, "using '%s' for '%s'\n", matches[0].key, name)
+		return names[matches[0].key], nil
+	}
+
+	if len(matches) == 0 {
+		return name, fmt.Errorf("no such %s: '%s'", kind, name)
+	}
+
+	l := make([]string, 0, maxSuggestions)
+	for i := 0; i < len(matches) && i < maxSuggestions; i++ {
+		l = append(l, matches[i].key)
+	}
+
+	return name, fmt.Errorf("no such %s: '%s', did you mean: %s", kind, name, strings.Join(l, ", "))
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/frizinak/devcalc/devchart"
+)
+
+func setOptions(o devchart.Options) {
+	options = &o
+	stripMap = make(map[string]string)
+	stripKinds = map[string]map[string]string{
+		"developer": make(map[string]string),
+		"stock":     make(map[string]string),
+	}
+	for _, k := range o.Developers {
+		stripMap[strip(k)] = k
+		stripKinds["developer"][strip(k)] = k
+	}
+	for _, k := range o.Stocks {
+		stripMap[strip(k)] = k
+		stripKinds["stock"][strip(k)] = k
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"rodinal", "rodinal", 0},
+		{"rodnial", "rodinal", 1},
+		{"rodinl", "rodinal", 1},
+		{"rodinaal", "rodinal", 1},
+		{"xtol", "d76", 4},
+		{"", "abc", 3},
+	}
+
+	for _, tt := range tests {
+		if got := distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("distance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	defer func() { options, stripMap, stripKinds = nil, nil, nil }()
+	setOptions(devchart.Options{
+		Developers: []string{"Rodinal", "D-76", "D-23", "D-19", "HC-110"},
+		Stocks:     []string{"Kentmere 400", "Ilford HP5+", "Rollei RPX 400"},
+	})
+
+	tests := []struct {
+		kind, name string
+		want       string
+		err        string
+	}{
+		{"developer", "rodinal", "Rodinal", ""},
+		{"developer", "rodnial", "Rodinal", ""},
+		{"developer", "hc110", "HC-110", ""},
+		{"developer", "d-26", "", "did you mean: d23, d76, d19"},
+		{"developer", "kentmere400", "", "no such developer: 'kentmere400'"},
+		{"developer", "kentmere40", "", "no such developer: 'kentmere40'"},
+		{"stock", "kentmere40", "Kentmere 400", ""},
+		{"stock", "rodnial", "", "no such stock: 'rodnial'"},
+	}
+
+	for _, tt := range tests {
+		got, err := resolve(tt.kind, tt.name)
+		switch {
+		case tt.err != "":
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("resolve(%q, %q) = %q, %v, want error %q", tt.kind, tt.name, got, err, tt.err)
+			}
+		case err != nil || got != tt.want:
+			t.Errorf("resolve(%q, %q) = %q, %v, want %q", tt.kind, tt.name, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"os"
+	"regexp"
+
+	"github.com/containerd/console"
+	"github.com/frizinak/devcalc/devchart"
+)
+
+const (
+	hlStart = "\033[48;5;52m\033[38;5;255m"
+	hlEnd   = "\033[0m"
+)
+
+// cachedEntries returns the cached entries of all developers matching devRE
+// (all if nil) from all sources without fetching anything.
+func cachedEntries(devRE *regexp.Regexp) ([]devchart.Entry, error) {
+	var entries []devchart.Entry
+	o, err := getOptions()
+	if err != nil {
+		return nil, err
+	}
+	for _, dev := range o.Developers {
+		if devRE != nil && !matchName(devRE, dev) {
+			continue
+		}
+		es, _ := getCache().Peek(dev)
+		entries = append(entries, es...)
+	}
+
+	srcs, err := localSources()
+	if err != nil {
+		return nil, err
+	}
+	for _, src := range srcs {
+		devs, err := src.Developers(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		for _, dev := range devs {
+			if devRE != nil && !matchName(devRE, dev) {
+				continue
+			}
+			es, err := src.Developer(context.Background(), dev)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, es...)
+		}
+	}
+
+	return mergeLocal(entries), nil
+}
+
+// grepNotes returns the entries with a note matching re, if mark is true the
+// matches are highlighted in the notes of the returned entries.
+func grepNotes(entries []devchart.Entry, re *regexp.Regexp, mark bool) []devchart.Entry {
+	_, err := console.ConsoleFromFile(os.Stdout)
+	term := err == nil
+
+	l := make([]devchart.Entry, 0)
+	for _, e := range entries {
+		var found bool
+		notes := make([]string, len(e.Notes))
+		for i, n := range e.Notes {
+			notes[i] = n
+			if !re.MatchString(n) {
+				continue
+			}
+
+			found = true
+			switch {
+			case !mark:
+			case term:
+				notes[i] = re.ReplaceAllStringFunc(n, func(m string) string {
+					return hlStart + m + hlEnd
+				})
+			default:
+				notes[i] = "> " + n
+			}
+		}
+
+		if found {
+			e.Notes = notes
+			l = append(l, e)
+		}
+	}
+
+	return l
+}
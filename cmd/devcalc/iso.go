@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/frizinak/devcalc/devchart"
+)
+
+// isoFilter matches the speed of entries against any of its conditions.
+type isoFilter []func(iso float64) bool
+
+// parseISOFilter parses a comma separated list of speeds (400), ranges
+// (400-1600) and comparisons (>=800, >800, <=800, <800).
+func parseISOFilter(s string) (isoFilter, error) {
+	var f isoFilter
+	for _, part := range strings.Split(s, ",") {
+		cond, err := parseISOCondition(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("invalid iso filter '%s': %w", s, err)
+		}
+		f = append(f, cond)
+	}
+
+	return f, nil
+}
+
+func parseISOCondition(s string) (func(float64) bool, error) {
+	num := func(s string) (float64, error) {
+		iso, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil || iso <= 0 {
+			return 0, fmt.Errorf("invalid iso: '%s'", s)
+		}
+		return iso, nil
+	}
+
+	for _, op := range []string{">=", "<=", ">", "<", "="} {
+		if !strings.HasPrefix(s, op) {
+			continue
+		}
+
+		n, err := num(s[len(op):])
+		if err != nil {
+			return nil, err
+		}
+
+		switch op {
+		case ">=":
+			return func(iso float64) bool { return iso >= n }, nil
+		case "<=":
+			return func(iso float64) bool { return iso <= n }, nil
+		case ">":
+			return func(iso float64) bool { return iso > n }, nil
+		case "<":
+			return func(iso float64) bool { return iso < n }, nil
+		}
+		return func(iso float64) bool { return iso == n }, nil
+	}
+
+	if from, to, ok := strings.Cut(s, "-"); ok {
+		lo, err := num(from)
+		if err != nil {
+			return nil, err
+		}
+		hi, err := num(to)
+		if err != nil {
+			return nil, err
+		}
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+		return func(iso float64) bool { return iso >= lo && iso <= hi }, nil
+	}
+
+	if n, ok := devchart.ParseISO(s); ok && n > 0 {
+		return func(iso float64) bool { return iso == n }, nil
+	}
+
+	return nil, fmt.Errorf("invalid iso: '%s'", s)
+}
+
+// match reports whether the speed of the ISO column iso matches f.
+func (f isoFilter) match(iso string) bool {
+	n, ok := devchart.ParseISO(iso)
+	if !ok {
+		return false
+	}
+
+	for _, cond := range f {
+		if cond(n) {
+			return true
+		}
+	}
+
+	return false
+}
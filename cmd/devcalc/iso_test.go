@@ -0,0 +1,71 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/frizinak/devcalc/devchart"
+)
+
+func TestParseISOFilter(t *testing.T) {
+	tests := []struct {
+		filter string
+		match  []string
+		miss   []string
+	}{
+		{"400", []string{"400", "EI 400", "400/27°"}, []string{"800", "4000", "", "n/a"}},
+		{"=400", []string{"400"}, []string{"800"}},
+		{">=800", []string{"800", "3200"}, []string{"400"}},
+		{">800", []string{"1600"}, []string{"800", "400"}},
+		{"<=800", []string{"800", "100"}, []string{"1600"}},
+		{"<800", []string{"400"}, []string{"800"}},
+		{"400-1600", []string{"400", "800", "1600"}, []string{"200", "3200"}},
+		{"1600-400", []string{"800"}, []string{"3200"}},
+		{"100, 400,>=3200", []string{"100", "400", "6400"}, []string{"200", "1600"}},
+	}
+
+	for _, tt := range tests {
+		f, err := parseISOFilter(tt.filter)
+		if err != nil {
+			t.Errorf("parseISOFilter(%q): %v", tt.filter, err)
+			continue
+		}
+		for _, iso := range tt.match {
+			if !f.match(iso) {
+				t.Errorf("%q does not match %q", tt.filter, iso)
+			}
+		}
+		for _, iso := range tt.miss {
+			if f.match(iso) {
+				t.Errorf("%q matches %q", tt.filter, iso)
+			}
+		}
+	}
+
+	for _, filter := range []string{"", "x", ">=", ">=x", "400-", "0", "400,,800"} {
+		if _, err := parseISOFilter(filter); err == nil {
+			t.Errorf("parseISOFilter(%q): expected an error", filter)
+		}
+	}
+}
+
+func TestCompareEntriesISO(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"400", "800", -1},
+		{"400", "x", -1},
+		{"x", "400", 1},
+		{"pull", "push", -1},
+		{"push", "pull", 1},
+		{"push", "push", 0},
+	}
+
+	for _, tt := range tests {
+		a := devchart.Entry{ISO: tt.a}
+		b := devchart.Entry{ISO: tt.b}
+		if got := compareEntries(a, b); got != tt.want {
+			t.Errorf("compareEntries(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
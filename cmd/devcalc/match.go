@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// rePrefix marks a name pattern as a regular expression instead of a glob.
+const rePrefix = "re:"
+
+// isPattern reports whether s is a name pattern rather than a single name.
+func isPattern(s string) bool {
+	return strings.HasPrefix(s, rePrefix) || strings.ContainsAny(s, "*?[{")
+}
+
+// compilePattern compiles a developer or stock name pattern.
+// Patterns prefixed with rePrefix are case-insensitive regular expressions
+// matched anywhere in the name, all others are globs supporting *, ?, [abc],
+// [!abc] and {a,b} that have to match the whole stripped name.
+func compilePattern(s string) (*regexp.Regexp, error) {
+	if strings.HasPrefix(s, rePrefix) {
+		re, err := regexp.Compile("(?i)" + strings.TrimPrefix(s, rePrefix))
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern '%s': %w", s, err)
+		}
+		return re, nil
+	}
+
+	expr, err := globRE(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern '%s': %w", s, err)
+	}
+
+	return regexp.Compile("(?i)^" + expr + "$")
+}
+
+// globRE translates glob to a regular expression, spaces and dashes are
+// dropped like they are by strip.
+func globRE(glob string) (string, error) {
+	var b strings.Builder
+	var braces int
+	rs := []rune(glob)
+	for i := 0; i < len(rs); i++ {
+		switch r := rs[i]; r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '{':
+			braces++
+			b.WriteString("(?:")
+		case '}':
+			if braces == 0 {
+				return "", fmt.Errorf("unmatched '}'")
+			}
+			braces--
+			b.WriteString(")")
+		case ',':
+			if braces == 0 {
+				b.WriteString(",")
+				continue
+			}
+			b.WriteString("|")
+		case '[':
+			end := i + 1
+			if end < len(rs) && rs[end] == '!' {
+				end++
+			}
+			if end < len(rs) && rs[end] == ']' {
+				end++
+			}
+			for end < len(rs) && rs[end] != ']' {
+				end++
+			}
+			if end == len(rs) {
+				return "", fmt.Errorf("unmatched '['")
+			}
+
+			class := rs[i+1 : end]
+			b.WriteString("[")
+			if len(class) != 0 && class[0] == '!' {
+				b.WriteString("^")
+				class = class[1:]
+			}
+			for _, c := range class {
+				if c == '\\' || c == '[' || c == ']' {
+					b.WriteRune('\\')
+				}
+				b.WriteRune(c)
+			}
+			b.WriteString("]")
+			i = end
+		case '\\':
+			if i+1 < len(rs) {
+				i++
+				b.WriteString(regexp.QuoteMeta(string(rs[i])))
+			}
+		case ' ', '-':
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	if braces != 0 {
+		return "", fmt.Errorf("unmatched '{'")
+	}
+
+	return b.String(), nil
+}
+
+// matchName reports whether re matches name, its stripped form or its
+// stripped form without leading words (e.g. hp5+ for Ilford HP5+).
+func matchName(re *regexp.Regexp, name string) bool {
+	if re.MatchString(name) {
+		return true
+	}
+
+	words := strings.Fields(name)
+	for i := range words {
+		if re.MatchString(strip(strings.Join(words[i:], " "))) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchDevelopers returns the developers of all sources that match pattern.
+func matchDevelopers(pattern string) ([]string, error) {
+	re, err := compilePattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	srcs, err := getSources()
+	if err != nil {
+		return nil, err
+	}
+
+	var l []string
+	seen := make(map[string]struct{})
+	for _, src := range srcs {
+		devs, err := src.Developers(context.Background())
+		if err = stale(err); err != nil {
+			return nil, err
+		}
+		for _, d := range devs {
+			if _, ok := seen[strip(d)]; ok || !matchName(re, d) {
+				continue
+			}
+			seen[strip(d)] = struct{}{}
+			l = append(l, d)
+		}
+	}
+
+	if len(l) == 0 {
+		return nil, fmt.Errorf("no developers match '%s'", pattern)
+	}
+
+	return l, nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import "testing"
+
+func TestGlobRE(t *testing.T) {
+	tests := []struct {
+		glob string
+		want string
+	}{
+		{"kentmere400", `kentmere400`},
+		{"hp5+", `hp5\+`},
+		{"kent*", `kent.*`},
+		{"hp?+", `hp.\+`},
+		{"ilford hp5-plus", `ilfordhp5plus`},
+		{"kentmere{100,400}", `kentmere(?:100|400)`},
+		{"{a,b{c,d}}", `(?:a|b(?:c|d))`},
+		{"a,b", `a,b`},
+		{"hp[45]", `hp[45]`},
+		{"hp[!45]", `hp[^45]`},
+		{"a[]]", `a[\]]`},
+		{`a\*`, `a\*`},
+		{"fp4.", `fp4\.`},
+	}
+
+	for _, tt := range tests {
+		got, err := globRE(tt.glob)
+		if err != nil {
+			t.Errorf("globRE(%q): %v", tt.glob, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("globRE(%q) = %q, want %q", tt.glob, got, tt.want)
+		}
+	}
+
+	for _, glob := range []string{"a}", "{a", "[a", "hp[!"} {
+		if got, err := globRE(glob); err == nil {
+			t.Errorf("globRE(%q) = %q, want error", glob, got)
+		}
+	}
+}
+
+func TestMatchName(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"kent*", "Kentmere 400", true},
+		{"kentmere400", "Kentmere 400", true},
+		{"kent*", "Ilford HP5+", false},
+		{"hp5*", "Ilford HP5+", true},
+		{"hp5+", "Ilford HP5+", true},
+		{"hp5", "Ilford HP5+", false},
+		{"*{100,400}", "Kentmere 400", true},
+		{"*{100,400}", "Kentmere 200", false},
+		{"re:hp5", "Ilford HP5+", true},
+		{"re:^kent", "Ilford HP5+", false},
+		{"re:delta.*100", "Ilford Delta 100 Professional", true},
+	}
+
+	for _, tt := range tests {
+		re, err := compilePattern(tt.pattern)
+		if err != nil {
+			t.Errorf("compilePattern(%q): %v", tt.pattern, err)
+			continue
+		}
+		if got := matchName(re, tt.name); got != tt.want {
+			t.Errorf("%q matches %q = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,575 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/frizinak/devcalc/dev"
+	"github.com/frizinak/devcalc/devchart"
+)
+
+// Output is the format in which results are written, see README.md for the
+// schemas of each format.
+type Output string
+
+const (
+	OutputText     Output = "text"
+	OutputJSON     Output = "json"
+	OutputCSV      Output = "csv"
+	OutputMarkdown Output = "markdown"
+)
+
+func (o Output) String() string { return string(o) }
+
+// Set implements flag.Value.
+func (o *Output) Set(s string) error {
+	switch n := Output(strings.ToLower(s)); n {
+	case OutputText, OutputJSON, OutputCSV, OutputMarkdown:
+		*o = n
+		return nil
+	}
+
+	return fmt.Errorf("invalid output format: '%s'", s)
+}
+
+type timeRecord struct {
+	Raw    string       `json:"raw"`
+	Min    int          `json:"min"`
+	Max    int          `json:"max"`
+	Stages []spanRecord `json:"stages"`
+}
+
+type spanRecord struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+func seconds(d time.Duration) int { return int(d.Seconds()) }
+
+func newTimeRecord(t devchart.Time) *timeRecord {
+	if t.Raw == "" {
+		return nil
+	}
+
+	total := t.Total()
+	r := &timeRecord{
+		Raw:    t.Raw,
+		Min:    seconds(total.Min),
+		Max:    seconds(total.Max),
+		Stages: make([]spanRecord, 0, len(t.Stages)),
+	}
+	for _, s := range t.Stages {
+		r.Stages = append(r.Stages, spanRecord{seconds(s.Min), seconds(s.Max)})
+	}
+
+	return r
+}
+
+type entryRecord struct {
+	Developer    string      `json:"developer"`
+	Film         string      `json:"film"`
+	ISO          string      `json:"iso"`
+	Dilution     string      `json:"dilution"`
+	DilutionCode string      `json:"dilution_code"`
+	Temp         *float64    `json:"temp"`
+	TempUnit     string      `json:"temp_unit"`
+	T135         *timeRecord `json:"135"`
+	T120         *timeRecord `json:"120"`
+	TSheet       *timeRecord `json:"sheet"`
+	Notes        []string    `json:"notes"`
+	Source       string      `json:"source"`
+	// Adjusted is only set when times are adjusted to another temperature.
+	Adjusted *adjustedRecord `json:"adjusted"`
+}
+
+type adjustedRecord struct {
+	Temp   float64     `json:"temp"`
+	T135   *timeRecord `json:"135"`
+	T120   *timeRecord `json:"120"`
+	TSheet *timeRecord `json:"sheet"`
+}
+
+func newAdjustedRecord(e devchart.Entry) *adjustedRecord {
+	r := &adjustedRecord{Temp: units.FromCelsius(atTemp)}
+	var ok bool
+	for _, t := range []struct {
+		t   devchart.Time
+		rec **timeRecord
+	}{{e.T135, &r.T135}, {e.T120, &r.T120}, {e.TSheet, &r.TSheet}} {
+		if a, adjusted := adjust(e, t.t); adjusted {
+			*t.rec = newTimeRecord(a)
+			ok = true
+		}
+	}
+	if !ok {
+		return nil
+	}
+
+	return r
+}
+
+func newEntryRecord(e devchart.Entry) entryRecord {
+	var temp *float64
+	if e.Temp != 0 {
+		t := units.FromCelsius(e.Temp)
+		temp = &t
+	}
+
+	notes := e.Notes
+	if notes == nil {
+		notes = []string{}
+	}
+
+	return entryRecord{
+		Developer:    e.Developer,
+		Film:         e.Name,
+		ISO:          e.ISO,
+		Dilution:     e.Dilution,
+		DilutionCode: e.DilutionCode,
+		Temp:         temp,
+		TempUnit:     units.String(),
+		T135:         newTimeRecord(e.T135),
+		T120:         newTimeRecord(e.T120),
+		TSheet:       newTimeRecord(e.TSheet),
+		Notes:        notes,
+		Source:       e.Source,
+		Adjusted:     newAdjustedRecord(e),
+	}
+}
+
+// chartEntryRecord is an entry of the local chart and its id.
+type chartEntryRecord struct {
+	ID int `json:"id"`
+	entryRecord
+}
+
+type estimateRecord struct {
+	Entry        entryRecord   `json:"entry"`
+	From         []entryRecord `json:"from"`
+	Extrapolated bool          `json:"extrapolated"`
+}
+
+func newEstimateRecord(est devchart.Estimate) estimateRecord {
+	from := slices.Clone(est.From)
+	sortEntries(from)
+
+	r := estimateRecord{
+		Entry:        newEntryRecord(est.Entry),
+		From:         make([]entryRecord, 0, len(from)),
+		Extrapolated: est.Extrapolated,
+	}
+	for _, e := range from {
+		r.From = append(r.From, newEntryRecord(e))
+	}
+
+	return r
+}
+
+type resultRecord struct {
+	ChemVolume  float64      `json:"chem_volume"`
+	ChemWeight  float64      `json:"chem_weight"`
+	WaterVolume float64      `json:"water_volume"`
+	TotalVolume float64      `json:"total_volume"`
+	TotalWeight float64      `json:"total_weight"`
+	Parts       []partRecord `json:"parts"`
+}
+
+type partRecord struct {
+	Volume float64 `json:"volume"`
+	Weight float64 `json:"weight"`
+}
+
+func newResultRecord(r dev.Result) resultRecord {
+	rec := resultRecord{
+		ChemVolume:  r.ChemVolume,
+		ChemWeight:  r.ChemWeight,
+		WaterVolume: r.WaterVolume,
+		TotalVolume: r.ChemVolume + r.WaterVolume,
+		Parts:       []partRecord{{r.ChemVolume, r.ChemWeight}},
+	}
+	if r.ChemWeight != 0 {
+		rec.TotalWeight = r.ChemWeight + r.WaterVolume
+	}
+	if len(r.Parts) != 0 {
+		rec.Parts = make([]partRecord, len(r.Parts))
+		for i, p := range r.Parts {
+			rec.Parts[i] = partRecord{p.Volume, p.Weight}
+		}
+	}
+
+	return rec
+}
+
+type refreshRecord struct {
+	Developer string `json:"developer"`
+	Entries   int    `json:"entries"`
+}
+
+type changeRecord struct {
+	Developer       string       `json:"developer"`
+	PreviousFetched time.Time    `json:"previous_fetched"`
+	Fetched         time.Time    `json:"fetched"`
+	Change          string       `json:"change"`
+	Old             *entryRecord `json:"old"`
+	New             *entryRecord `json:"new"`
+	Fields          []string     `json:"fields"`
+}
+
+// diffRecord holds the changes of a single developer.
+type diffRecord struct {
+	Developer       string
+	PreviousFetched time.Time
+	Fetched         time.Time
+	Changes         []devchart.Change
+}
+
+func newChangeRecords(d diffRecord) []changeRecord {
+	l := make([]changeRecord, 0, len(d.Changes))
+	for _, c := range d.Changes {
+		r := changeRecord{
+			Developer:       d.Developer,
+			PreviousFetched: d.PreviousFetched.Truncate(time.Second),
+			Fetched:         d.Fetched.Truncate(time.Second),
+			Fields:          []string{},
+		}
+		switch {
+		case c.Old == nil:
+			r.Change = "added"
+		case c.New == nil:
+			r.Change = "removed"
+		default:
+			r.Change = "changed"
+			r.Fields = entryChanges(*c.Old, *c.New)
+		}
+		if c.Old != nil {
+			o := newEntryRecord(*c.Old)
+			r.Old = &o
+		}
+		if c.New != nil {
+			n := newEntryRecord(*c.New)
+			r.New = &n
+		}
+		l = append(l, r)
+	}
+
+	return l
+}
+
+type optionRecord struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// table is the tabular representation of records used for csv and markdown.
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+func fmtFloat(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+
+func entriesTable(entries []devchart.Entry) table {
+	t := table{header: []string{
+		"developer", "film", "iso", "dilution", "dilution_code", "temp", "temp_unit",
+		"135", "135_min", "135_max",
+		"120", "120_min", "120_max",
+		"sheet", "sheet_min", "sheet_max",
+		"notes", "source",
+		"adjusted_temp",
+		"adjusted_135", "adjusted_135_min", "adjusted_135_max",
+		"adjusted_120", "adjusted_120_min", "adjusted_120_max",
+		"adjusted_sheet", "adjusted_sheet_min", "adjusted_sheet_max",
+	}}
+
+	for _, e := range entries {
+		r := newEntryRecord(e)
+		var temp string
+		if r.Temp != nil {
+			temp = fmtFloat(*r.Temp)
+		}
+
+		row := []string{r.Developer, r.Film, r.ISO, r.Dilution, r.DilutionCode, temp, r.TempUnit}
+		row = appendTimes(row, r.T135, r.T120, r.TSheet)
+		row = append(row, strings.Join(r.Notes, "\n"), r.Source)
+		if r.Adjusted == nil {
+			row = appendTimes(append(row, ""), nil, nil, nil)
+		} else {
+			row = append(row, fmtFloat(r.Adjusted.Temp))
+			row = appendTimes(row, r.Adjusted.T135, r.Adjusted.T120, r.Adjusted.TSheet)
+		}
+		t.rows = append(t.rows, row)
+	}
+
+	return t
+}
+
+// estimatesTable has a row for each listed entry, marked listed, and for each
+// estimate, marked estimated or extrapolated, followed by a row for each entry
+// it was derived from, marked from. The rows of an estimate share its
+// estimate_id.
+func estimatesTable(listed []devchart.Entry, estimates []devchart.Estimate) table {
+	t := table{header: []string{"estimate", "estimate_id"}}
+	t.header = append(t.header, entriesTable(nil).header...)
+
+	for _, row := range entriesTable(listed).rows {
+		t.rows = append(t.rows, append([]string{"listed", ""}, row...))
+	}
+	for i, est := range estimates {
+		id := strconv.Itoa(i + 1)
+		mark := "estimated"
+		if est.Extrapolated {
+			mark = "extrapolated"
+		}
+		from := slices.Clone(est.From)
+		sortEntries(from)
+
+		t.rows = append(t.rows, append([]string{mark, id}, entriesTable([]devchart.Entry{est.Entry}).rows[0]...))
+		for _, row := range entriesTable(from).rows {
+			t.rows = append(t.rows, append([]string{"from", id}, row...))
+		}
+	}
+
+	return t
+}
+
+func appendTimes(row []string, times ...*timeRecord) []string {
+	for _, tr := range times {
+		if tr == nil {
+			row = append(row, "", "", "")
+			continue
+		}
+		row = append(row, tr.Raw, strconv.Itoa(tr.Min), strconv.Itoa(tr.Max))
+	}
+
+	return row
+}
+
+func resultTable(r dev.Result) table {
+	rec := newResultRecord(r)
+	vols := make([]string, len(rec.Parts))
+	weights := make([]string, len(rec.Parts))
+	for i, p := range rec.Parts {
+		vols[i], weights[i] = fmtFloat(p.Volume), fmtFloat(p.Weight)
+	}
+
+	return table{
+		header: []string{
+			"chem_volume", "chem_weight", "water_volume", "total_volume", "total_weight",
+			"part_volumes", "part_weights",
+		},
+		rows: [][]string{{
+			fmtFloat(rec.ChemVolume),
+			fmtFloat(rec.ChemWeight),
+			fmtFloat(rec.WaterVolume),
+			fmtFloat(rec.TotalVolume),
+			fmtFloat(rec.TotalWeight),
+			strings.Join(vols, " "),
+			strings.Join(weights, " "),
+		}},
+	}
+}
+
+func chartTable(ids []int, entries []devchart.Entry) table {
+	et := entriesTable(entries)
+	t := table{header: append([]string{"id"}, et.header...)}
+	for i, row := range et.rows {
+		t.rows = append(t.rows, append([]string{strconv.Itoa(ids[i])}, row...))
+	}
+
+	return t
+}
+
+func refreshTable(l []refreshRecord) table {
+	t := table{header: []string{"developer", "entries"}}
+	for _, r := range l {
+		t.rows = append(t.rows, []string{r.Developer, strconv.Itoa(r.Entries)})
+	}
+
+	return t
+}
+
+// changesTable has a row for each change holding the new entry, or the old
+// one if it was removed.
+func changesTable(diffs []diffRecord) table {
+	t := table{header: []string{"previous_fetched", "fetched", "change"}}
+	t.header = append(t.header, entriesTable(nil).header...)
+	t.header = append(t.header, "fields")
+
+	for _, d := range diffs {
+		for i, r := range newChangeRecords(d) {
+			row := []string{
+				r.PreviousFetched.Format(time.RFC3339),
+				r.Fetched.Format(time.RFC3339),
+				r.Change,
+			}
+			row = append(row, entriesTable([]devchart.Entry{changeEntry(d.Changes[i])}).rows[0]...)
+			row = append(row, strings.Join(r.Fields, "\n"))
+			t.rows = append(t.rows, row)
+		}
+	}
+
+	return t
+}
+
+func optionsTable(opts []optionRecord) table {
+	t := table{header: []string{"id", "name"}}
+	for _, o := range opts {
+		t.rows = append(t.rows, []string{o.ID, o.Name})
+	}
+
+	return t
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func writeTable(w io.Writer, t table) error {
+	switch output {
+	case OutputCSV:
+		c := csv.NewWriter(w)
+		c.Write(t.header)
+		c.WriteAll(t.rows)
+		return c.Error()
+	case OutputMarkdown:
+		esc := strings.NewReplacer("|", "\\|", "\n", "<br>")
+		line := func(cells []string) {
+			fmt.Fprint(w, "|")
+			for _, c := range cells {
+				fmt.Fprintf(w, " %s |", esc.Replace(c))
+			}
+			fmt.Fprintln(w)
+		}
+		line(t.header)
+		sep := make([]string, len(t.header))
+		for i := range sep {
+			sep[i] = "---"
+		}
+		line(sep)
+		for _, r := range t.rows {
+			line(r)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("can not write a table as %s", output)
+}
+
+func writeEntries(w io.Writer, entries []devchart.Entry) error {
+	if output == OutputJSON {
+		l := make([]entryRecord, 0, len(entries))
+		for _, e := range entries {
+			l = append(l, newEntryRecord(e))
+		}
+		return writeJSON(w, l)
+	}
+
+	return writeTable(w, entriesTable(entries))
+}
+
+// writeChartEntries writes entries of the local chart with their ids.
+func writeChartEntries(w io.Writer, ids []int, entries []devchart.Entry) error {
+	if output == OutputJSON {
+		l := make([]chartEntryRecord, 0, len(entries))
+		for i, e := range entries {
+			l = append(l, chartEntryRecord{ids[i], newEntryRecord(e)})
+		}
+		return writeJSON(w, l)
+	}
+
+	return writeTable(w, chartTable(ids, entries))
+}
+
+func writeOptions(w io.Writer, opts []optionRecord) error {
+	if output == OutputJSON {
+		return writeJSON(w, opts)
+	}
+
+	return writeTable(w, optionsTable(opts))
+}
+
+func writeRefreshed(w io.Writer, l []refreshRecord) error {
+	if output == OutputJSON {
+		return writeJSON(w, l)
+	}
+
+	return writeTable(w, refreshTable(l))
+}
+
+func writeChanges(w io.Writer, diffs []diffRecord) error {
+	if output == OutputJSON {
+		l := make([]changeRecord, 0)
+		for _, d := range diffs {
+			l = append(l, newChangeRecords(d)...)
+		}
+		return writeJSON(w, l)
+	}
+
+	return writeTable(w, changesTable(diffs))
+}
+
+// writeEstimates writes the entries that were listed instead of estimated
+// and the estimates.
+func writeEstimates(w io.Writer, listed []devchart.Entry, estimates []devchart.Estimate) error {
+	if output == OutputJSON {
+		v := struct {
+			Entries   []entryRecord    `json:"entries"`
+			Estimates []estimateRecord `json:"estimates"`
+		}{
+			Entries:   make([]entryRecord, 0, len(listed)),
+			Estimates: make([]estimateRecord, 0, len(estimates)),
+		}
+		for _, e := range listed {
+			v.Entries = append(v.Entries, newEntryRecord(e))
+		}
+		for _, est := range estimates {
+			v.Estimates = append(v.Estimates, newEstimateRecord(est))
+		}
+		return writeJSON(w, v)
+	}
+
+	return writeTable(w, estimatesTable(listed, estimates))
+}
+
+// writeCalc writes the result of calc and, if withEntries is true, the
+// matching entries and the estimates for the stocks and speeds that do not
+// list the ratio.
+func writeCalc(w io.Writer, r dev.Result, entries []devchart.Entry, estimates []devchart.Estimate, withEntries bool) error {
+	if output == OutputJSON {
+		v := struct {
+			Result    resultRecord     `json:"result"`
+			Entries   []entryRecord    `json:"entries"`
+			Estimates []estimateRecord `json:"estimates"`
+		}{Result: newResultRecord(r)}
+		if withEntries {
+			v.Entries = make([]entryRecord, 0, len(entries))
+			for _, e := range entries {
+				v.Entries = append(v.Entries, newEntryRecord(e))
+			}
+			v.Estimates = make([]estimateRecord, 0, len(estimates))
+			for _, est := range estimates {
+				v.Estimates = append(v.Estimates, newEstimateRecord(est))
+			}
+		}
+		return writeJSON(w, v)
+	}
+
+	if err := writeTable(w, resultTable(r)); err != nil || !withEntries {
+		return err
+	}
+	fmt.Fprintln(w)
+	if err := writeTable(w, entriesTable(entries)); err != nil || len(estimates) == 0 {
+		return err
+	}
+	fmt.Fprintln(w)
+
+	return writeTable(w, estimatesTable(nil, estimates))
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/frizinak/devcalc/devchart"
+)
+
+func TestEstimatesTable(t *testing.T) {
+	e := func(dil, iso, t135 string) devchart.Entry {
+		return devchart.Entry{Name: "Kentmere 400", Developer: "Rodinal", Dilution: dil, ISO: iso, T135: devchart.ParseTime(t135)}
+	}
+	estimates := []devchart.Estimate{
+		{Entry: e("1+30", "400", "10"), From: []devchart.Entry{e("1+50", "400", "13"), e("1+25", "400", "9")}},
+		{Entry: e("1+30", "800", "5"), From: []devchart.Entry{e("1+40", "800", "7")}, Extrapolated: true},
+	}
+
+	listed := []devchart.Entry{e("1+30", "1600", "12")}
+
+	tbl := estimatesTable(listed, estimates)
+	got := make([][]string, 0, len(tbl.rows))
+	for _, row := range tbl.rows {
+		// estimate, estimate_id, dilution and iso.
+		got = append(got, []string{row[0], row[1], row[5], row[4]})
+	}
+	want := [][]string{
+		{"listed", "", "1+30", "1600"},
+		{"estimated", "1", "1+30", "400"},
+		{"from", "1", "1+25", "400"},
+		{"from", "1", "1+50", "400"},
+		{"extrapolated", "2", "1+30", "800"},
+		{"from", "2", "1+40", "800"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
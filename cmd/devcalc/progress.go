@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// progress prints a single, continuously updated status line.
+type progress struct {
+	mu    sync.Mutex
+	w     io.Writer
+	total int
+	done  int
+}
+
+func newProgress(w io.Writer, total int) *progress {
+	return &progress{w: w, total: total}
+}
+
+func (p *progress) clear() { fmt.Fprint(p.w, "\r\033[K") }
+
+// Step marks one item as done and prints its label.
+func (p *progress) Step(label string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	p.clear()
+	fmt.Fprintf(p.w, "[%d/%d] %s", p.done, p.total, label)
+}
+
+// Warn prints err on its own line without garbling the status line.
+func (p *progress) Warn(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clear()
+	warn(err)
+}
+
+// Done clears the status line.
+func (p *progress) Done() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clear()
+}
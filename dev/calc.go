@@ -1,27 +1,52 @@
 package dev
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type Result struct {
 	ChemVolume  float64
 	ChemWeight  float64
 	WaterVolume float64
+	// Parts is the result of each chemical of a PartsChem.
+	Parts []Part
+}
+
+// Part is the volume and weight of a single chemical.
+type Part struct {
+	Volume float64
+	Weight float64
 }
 
 func (r Result) String() string {
+	chem := fmt.Sprintf("%.2fml", r.ChemVolume)
+	if r.ChemWeight != 0 || r.ChemVolume == 0 {
+		chem = fmt.Sprintf("%.2fml (%.2fg)", r.ChemVolume, r.ChemWeight)
+	}
+	if len(r.Parts) > 1 {
+		parts := make([]string, len(r.Parts))
+		for i, p := range r.Parts {
+			parts[i] = fmt.Sprintf("%.2fml", p.Volume)
+			if p.Weight != 0 {
+				parts[i] = fmt.Sprintf("%.2fml (%.2fg)", p.Volume, p.Weight)
+			}
+		}
+		chem = strings.Join(parts, " + ")
+	}
+
 	if r.ChemWeight == 0 && r.ChemVolume != 0 {
 		return fmt.Sprintf(
-			"%.2fml + %2.fml = %.2fml",
-			r.ChemVolume,
+			"%s + %2.fml = %.2fml",
+			chem,
 			r.WaterVolume,
 			r.ChemVolume+r.WaterVolume,
 		)
 	}
 
 	return fmt.Sprintf(
-		"%.2fml (%.2fg) + %2.fml = %.2fml (%.2fg)",
-		r.ChemVolume,
-		r.ChemWeight,
+		"%s + %2.fml = %.2fml (%.2fg)",
+		chem,
 		r.WaterVolume,
 		r.ChemVolume+r.WaterVolume,
 		r.ChemWeight+r.WaterVolume,
@@ -30,6 +55,18 @@ func (r Result) String() string {
 
 func Calc(c Chem, volume float64) Result {
 	var r Result
+	if pc, ok := c.(PartsChem); ok {
+		for _, p := range pc.Parts() {
+			part := Calc(p, volume)
+			r.Parts = append(r.Parts, Part{part.ChemVolume, part.ChemWeight})
+			r.ChemVolume += part.ChemVolume
+			r.ChemWeight += part.ChemWeight
+		}
+		r.WaterVolume = volume - r.ChemVolume
+
+		return r
+	}
+
 	r.ChemVolume = c.Volume(volume)
 	r.ChemWeight = r.ChemVolume * c.Density()
 	r.WaterVolume = volume - r.ChemVolume
@@ -0,0 +1,113 @@
+package dev
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StandardTemp is the temperature in degrees Celsius most development times
+// are listed for.
+const StandardTemp = 20
+
+// Compensation adjusts development times to the temperature they are
+// developed at: every degree Celsius warmer shortens the time by Rate and
+// every degree colder lengthens it by the same factor.
+type Compensation struct {
+	// Rate is the fraction the time changes per degree Celsius, e.g. 0.08.
+	Rate float64
+}
+
+// DefaultCompensation approximates the time/temperature charts of Ilford and
+// Kodak, e.g. 10 minutes at 20°C is about 7 minutes at 24°C and 12 minutes
+// at 18°C.
+var DefaultCompensation = Compensation{Rate: 0.085}
+
+// Factor returns the factor to multiply a time at from with to get the time at
+// to, both in degrees Celsius.
+func (c Compensation) Factor(from, to float64) float64 {
+	return math.Pow(1-c.Rate, to-from)
+}
+
+// Adjust returns d at from adjusted to to, both in degrees Celsius.
+func (c Compensation) Adjust(d time.Duration, from, to float64) time.Duration {
+	return time.Duration(float64(d) * c.Factor(from, to)).Round(time.Second)
+}
+
+// Compensations maps developer name prefixes to their Compensation, the
+// prefix * matches all developers.
+type Compensations map[string]Compensation
+
+// DefaultCompensations uses DefaultCompensation for all developers.
+var DefaultCompensations = Compensations{"*": DefaultCompensation}
+
+// ReadCompensations reads compensations, one per line in the form of
+// '<developer prefix> <rate>' where rate is a fraction or percentage per
+// degree Celsius (e.g. 'Rodinal 0.09' or 'Rodinal 9%').
+// A prefix of * matches all developers, empty lines and lines starting with
+// # are ignored.
+func ReadCompensations(r io.Reader) (Compensations, error) {
+	c := make(Compensations)
+	scan := bufio.NewScanner(r)
+	for scan.Scan() {
+		text := strings.TrimSpace(scan.Text())
+		if text == "" || text[0] == '#' {
+			continue
+		}
+
+		f := strings.Fields(text)
+		if len(f) < 2 {
+			return c, fmt.Errorf("invalid compensation line '%s'", text)
+		}
+
+		rate := f[len(f)-1]
+		div := 1.0
+		if strings.HasSuffix(rate, "%") {
+			rate, div = strings.TrimSuffix(rate, "%"), 100
+		}
+		v, err := strconv.ParseFloat(rate, 64)
+		if err != nil || v < 0 || v/div >= 1 {
+			return c, fmt.Errorf("invalid compensation rate in line '%s'", text)
+		}
+
+		c[strings.Join(f[:len(f)-1], " ")] = Compensation{Rate: v / div}
+	}
+
+	return c, scan.Err()
+}
+
+// Merge returns the union of c and o, compensations in o take precedence.
+func (c Compensations) Merge(o Compensations) Compensations {
+	n := make(Compensations, len(c)+len(o))
+	for _, src := range []Compensations{c, o} {
+		for prefix, comp := range src {
+			n[prefix] = comp
+		}
+	}
+
+	return n
+}
+
+// For returns the Compensation of developer dev, the longest matching prefix
+// wins. DefaultCompensation is returned if no prefix matches.
+func (c Compensations) For(dev string) Compensation {
+	comp, match, ok := DefaultCompensation, "", false
+	for prefix, v := range c {
+		if prefix != "*" && !strings.HasPrefix(dev, prefix) {
+			continue
+		}
+		if prefix == "*" {
+			prefix = ""
+		}
+		if ok && len(prefix) < len(match) {
+			continue
+		}
+		comp, match, ok = v, prefix, true
+	}
+
+	return comp
+}
@@ -0,0 +1,60 @@
+package dev
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompensationsFor(t *testing.T) {
+	custom, err := ReadCompensations(strings.NewReader(`
+# comment
+* 10%
+Rodinal 0.09
+Rodinal Special 0.07
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		c    Compensations
+		dev  string
+		want float64
+	}{
+		{custom, "Rodinal", 0.09},
+		{custom, "Rodinal Special", 0.07},
+		{custom, "Rodinal Specialist", 0.07},
+		{custom, "HC-110", 0.10},
+		{Compensations{"Rodinal": {0.09}}, "HC-110", DefaultCompensation.Rate},
+		{DefaultCompensations.Merge(Compensations{"HC": {0.05}}), "HC-110", 0.05},
+		{DefaultCompensations.Merge(Compensations{"HC": {0.05}}), "D-76", DefaultCompensation.Rate},
+	}
+
+	for _, tt := range tests {
+		if got := tt.c.For(tt.dev).Rate; got != tt.want {
+			t.Errorf("For(%q) = %v, want %v", tt.dev, got, tt.want)
+		}
+	}
+}
+
+func TestReadCompensationsInvalid(t *testing.T) {
+	for _, in := range []string{"Rodinal", "Rodinal x", "Rodinal 100%", "Rodinal -0.1"} {
+		if _, err := ReadCompensations(strings.NewReader(in)); err == nil {
+			t.Errorf("ReadCompensations(%q): expected an error", in)
+		}
+	}
+}
+
+func TestCompensationAdjust(t *testing.T) {
+	c := DefaultCompensation
+	if got := c.Adjust(10*time.Minute, 20, 20); got != 10*time.Minute {
+		t.Errorf("Adjust at the same temperature = %v", got)
+	}
+	if got := c.Adjust(10*time.Minute, 20, 24); got < 6*time.Minute+50*time.Second || got > 7*time.Minute+10*time.Second {
+		t.Errorf("Adjust(10m, 20, 24) = %v, want about 7m", got)
+	}
+	if got := c.Adjust(c.Adjust(10*time.Minute, 20, 18), 18, 20); got != 10*time.Minute {
+		t.Errorf("Adjust there and back = %v", got)
+	}
+}
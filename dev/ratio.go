@@ -0,0 +1,167 @@
+package dev
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	perLiterRE   = regexp.MustCompile(`(?i)^([0-9.]+)\s*ml\s*/\s*(?:1\s*)?l$`)
+	chartRatioRE = regexp.MustCompile(`^[0-9.\s]+(?:[:/+][0-9.\s]+)+$`)
+)
+
+// Notation is the notation a Ratio was parsed from.
+type Notation byte
+
+const (
+	// NotationParts lists the parts of each chemical and water, e.g. 1+25.
+	NotationParts Notation = iota
+	// NotationTotal lists the parts of the chemical in the total, e.g. 1:26
+	// which is 1+25 in NotationParts.
+	NotationTotal
+	// NotationStock is an undiluted chemical.
+	NotationStock
+	// NotationPercent is the percentage of chemical, e.g. 5%.
+	NotationPercent
+	// NotationPerLiter is the ml of chemical per liter, e.g. 20ml/l.
+	NotationPerLiter
+)
+
+// Ratio is a dilution of one or more chemicals in water, e.g. 1+1+100 for
+// Pyrocat-HD (1 part A, 1 part B and 100 parts water).
+type Ratio struct {
+	// Parts of each chemical followed by the parts of water.
+	Parts []float64
+	// Notation the ratio was given in, Parts are always converted.
+	Notation Notation
+}
+
+// ParseRatio parses a ratio in any of the notations:
+//   - 1+25, 1+62.5 or 1+1+100: parts of each chemical followed by water.
+//   - 1:26: 1 part of chemical in 26 parts in total, i.e. 1+25.
+//   - stock: undiluted, i.e. 1+0.
+//   - 5%: percentage of chemical, i.e. 5+95.
+//   - 20ml/l: ml of chemical per liter, i.e. 20+980.
+func ParseRatio(s string) (Ratio, error) {
+	str := strings.TrimSpace(s)
+	invalid := func(reason string) (Ratio, error) {
+		return Ratio{}, fmt.Errorf("invalid ratio '%s': %s", s, reason)
+	}
+	num := func(n string) (float64, bool) {
+		v, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return v, err == nil && v >= 0
+	}
+
+	switch {
+	case strings.EqualFold(str, "stock"):
+		return Ratio{Parts: []float64{1, 0}, Notation: NotationStock}, nil
+
+	case strings.HasSuffix(str, "%"):
+		v, ok := num(strings.TrimSuffix(str, "%"))
+		if !ok || v == 0 || v > 100 {
+			return invalid("percentage should be a number larger than 0 and at most 100")
+		}
+		return Ratio{Parts: []float64{v, 100 - v}, Notation: NotationPercent}, nil
+
+	case perLiterRE.MatchString(str):
+		v, ok := num(perLiterRE.FindStringSubmatch(str)[1])
+		if !ok || v == 0 || v > 1000 {
+			return invalid("ml per liter should be a number larger than 0 and at most 1000")
+		}
+		return Ratio{Parts: []float64{v, 1000 - v}, Notation: NotationPerLiter}, nil
+
+	case strings.Contains(str, ":"):
+		p := strings.Split(str, ":")
+		if len(p) != 2 {
+			return invalid("total parts notation (1:26) only supports a single chemical, use + instead (1+1+100)")
+		}
+		chem, ok1 := num(p[0])
+		total, ok2 := num(p[1])
+		if !ok1 || !ok2 || chem == 0 || total < chem {
+			return invalid("the total (26 in 1:26) should be at least the parts of chemical")
+		}
+		return Ratio{Parts: []float64{chem, total - chem}, Notation: NotationTotal}, nil
+	}
+
+	p := strings.Split(str, "+")
+	if len(p) < 2 {
+		return invalid("expected parts of chemical and water (e.g. 1+25), a total (1:26), stock, a percentage (5%) or ml per liter (20ml/l)")
+	}
+
+	parts := make([]float64, len(p))
+	for i, n := range p {
+		v, ok := num(n)
+		if !ok {
+			return invalid(fmt.Sprintf("'%s' is not a positive number", n))
+		}
+		parts[i] = v
+	}
+	r := NewRatio(parts...)
+	if r.Total() == r.Water() {
+		return invalid("no parts of chemical")
+	}
+
+	return r, nil
+}
+
+// ParseChartRatio parses a dilution as listed in a development chart, where
+// ':', '/' and '+' all separate the parts of chemical and water (1:25, 1/25
+// and 1+25 are the same dilution). Other notations are parsed like
+// ParseRatio, total parts notation (1:26) is not supported.
+func ParseChartRatio(s string) (Ratio, error) {
+	str := strings.TrimSpace(s)
+	if !chartRatioRE.MatchString(str) {
+		return ParseRatio(str)
+	}
+
+	return ParseRatio(strings.NewReplacer(":", "+", "/", "+").Replace(str))
+}
+
+// NewRatio returns the Ratio of the given chemical parts followed by water.
+func NewRatio(parts ...float64) Ratio { return Ratio{Parts: parts} }
+
+// Valid reports whether r has at least one chemical and a water part.
+func (r Ratio) Valid() bool { return len(r.Parts) >= 2 && r.Total() > 0 }
+
+// Chem returns the parts of each chemical.
+func (r Ratio) Chem() []float64 {
+	if len(r.Parts) < 2 {
+		return nil
+	}
+	return r.Parts[:len(r.Parts)-1]
+}
+
+// Water returns the parts of water.
+func (r Ratio) Water() float64 {
+	if len(r.Parts) < 2 {
+		return 0
+	}
+	return r.Parts[len(r.Parts)-1]
+}
+
+// Total returns the sum of all parts.
+func (r Ratio) Total() float64 {
+	var t float64
+	for _, p := range r.Parts {
+		t += p
+	}
+	return t
+}
+
+// Fraction returns the fraction of all chemicals in the solution.
+func (r Ratio) Fraction() float64 {
+	if !r.Valid() {
+		return 0
+	}
+	return (r.Total() - r.Water()) / r.Total()
+}
+
+func (r Ratio) String() string {
+	l := make([]string, len(r.Parts))
+	for i, p := range r.Parts {
+		l[i] = strconv.FormatFloat(p, 'f', -1, 64)
+	}
+	return strings.Join(l, "+")
+}
@@ -0,0 +1,91 @@
+package dev
+
+import "testing"
+
+func TestParseChartRatio(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"1:25", "1+25"},
+		{"1+25", "1+25"},
+		{"1/25", "1+25"},
+		{"1 : 25", "1+25"},
+		{"1+1+100", "1+1+100"},
+		{"1:1:100", "1+1+100"},
+		{"1+62.5", "1+62.5"},
+		{"stock", "1+0"},
+		{"5%", "5+95"},
+		{"20ml/l", "20+980"},
+	}
+
+	for _, tt := range tests {
+		r, err := ParseChartRatio(tt.in)
+		if err != nil {
+			t.Errorf("ParseChartRatio(%q): %v", tt.in, err)
+			continue
+		}
+		if got := r.String(); got != tt.want {
+			t.Errorf("ParseChartRatio(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "B", "1", "0+25", "1:"} {
+		if r, err := ParseChartRatio(in); err == nil {
+			t.Errorf("ParseChartRatio(%q) = %s, want error", in, r)
+		}
+	}
+}
+
+func TestParseRatio(t *testing.T) {
+	tests := []struct {
+		in       string
+		want     string
+		notation Notation
+	}{
+		{"1+25", "1+25", NotationParts},
+		{"1:26", "1+25", NotationTotal},
+		{"1+1+100", "1+1+100", NotationParts},
+		{"1+62.5", "1+62.5", NotationParts},
+		{"stock", "1+0", NotationStock},
+		{"Stock", "1+0", NotationStock},
+		{"5%", "5+95", NotationPercent},
+		{"20ml/l", "20+980", NotationPerLiter},
+		{"20 ml / 1 l", "20+980", NotationPerLiter},
+	}
+
+	for _, tt := range tests {
+		r, err := ParseRatio(tt.in)
+		if err != nil {
+			t.Errorf("ParseRatio(%q): %v", tt.in, err)
+			continue
+		}
+		if got := r.String(); got != tt.want || r.Notation != tt.notation {
+			t.Errorf("ParseRatio(%q) = %s (%d), want %s (%d)", tt.in, got, r.Notation, tt.want, tt.notation)
+		}
+	}
+
+	for _, in := range []string{"", "1", "0+25", "1+x", "1:1:100", "2:1", "0%", "101%", "1/25"} {
+		if r, err := ParseRatio(in); err == nil {
+			t.Errorf("ParseRatio(%q) = %s, want error", in, r)
+		}
+	}
+}
+
+func TestRatioFraction(t *testing.T) {
+	tests := []struct {
+		r    Ratio
+		want float64
+	}{
+		{NewRatio(1, 25), 1.0 / 26},
+		{NewRatio(1, 1, 100), 2.0 / 102},
+		{NewRatio(1, 0), 1},
+		{NewRatio(1), 0},
+	}
+
+	for _, tt := range tests {
+		if got := tt.r.Fraction(); got != tt.want {
+			t.Errorf("%s.Fraction() = %v, want %v", tt.r, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package dev
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Unit is a temperature unit.
+type Unit byte
+
+const (
+	Celsius    Unit = 'C'
+	Fahrenheit Unit = 'F'
+)
+
+func ParseUnit(s string) (Unit, error) {
+	switch strings.ToUpper(strings.TrimPrefix(s, "°")) {
+	case "C", "CELSIUS":
+		return Celsius, nil
+	case "F", "FAHRENHEIT":
+		return Fahrenheit, nil
+	}
+
+	return 0, fmt.Errorf("invalid temperature unit: '%s'", s)
+}
+
+func (u Unit) String() string { return string(u) }
+
+// Set implements flag.Value.
+func (u *Unit) Set(s string) error {
+	n, err := ParseUnit(s)
+	if err != nil {
+		return err
+	}
+	*u = n
+	return nil
+}
+
+// FromCelsius converts c degrees Celsius to u.
+func (u Unit) FromCelsius(c float64) float64 {
+	if u == Fahrenheit {
+		return c*9/5 + 32
+	}
+	return c
+}
+
+// ToCelsius converts v in u to degrees Celsius.
+func (u Unit) ToCelsius(v float64) float64 {
+	if u == Fahrenheit {
+		return (v - 32) * 5 / 9
+	}
+	return v
+}
+
+// ParseTemp parses a temperature (e.g. 20, 20C, 68F, 68°F or 20°) and returns
+// it in degrees Celsius. Temperatures without a unit are in def, a degree sign
+// without a unit means Celsius.
+func ParseTemp(s string, def Unit) (float64, error) {
+	str := strings.TrimSpace(s)
+	unit := def
+	if strings.HasSuffix(str, "°") {
+		unit = Celsius
+		str = strings.TrimSpace(strings.TrimSuffix(str, "°"))
+	} else if l := len(str); l > 1 {
+		if u, err := ParseUnit(str[l-1:]); err == nil {
+			unit = u
+			str = strings.TrimSpace(strings.TrimSuffix(str[:l-1], "°"))
+		}
+	}
+
+	v, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid temperature: '%s'", s)
+	}
+
+	return unit.ToCelsius(v), nil
+}
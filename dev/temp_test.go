@@ -0,0 +1,44 @@
+package dev
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseTemp(t *testing.T) {
+	tests := []struct {
+		in   string
+		def  Unit
+		want float64
+	}{
+		{"20", Celsius, 20},
+		{"68", Fahrenheit, 20},
+		{"20C", Fahrenheit, 20},
+		{"20c", Fahrenheit, 20},
+		{"68F", Celsius, 20},
+		{"68°F", Celsius, 20},
+		{"20 °C", Fahrenheit, 20},
+		{" 24.5C ", Celsius, 24.5},
+		{"-4F", Celsius, -20},
+		{"20°", Fahrenheit, 20},
+		{"20 °", Celsius, 20},
+		{"24.5°", Celsius, 24.5},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseTemp(tt.in, tt.def)
+		if err != nil {
+			t.Errorf("ParseTemp(%q, %s): %v", tt.in, tt.def, err)
+			continue
+		}
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("ParseTemp(%q, %s) = %v, want %v", tt.in, tt.def, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "C", "°", "warm", "20K", "20CF", "20°°"} {
+		if got, err := ParseTemp(in, Celsius); err == nil {
+			t.Errorf("ParseTemp(%q) = %v, want error", in, got)
+		}
+	}
+}
@@ -1,16 +1,17 @@
 package dev
 
-import (
-	"fmt"
-	"strconv"
-	"strings"
-)
-
 type Chem interface {
 	Density() float64
 	Volume(float64) float64
 }
 
+// PartsChem is a Chem that consists of multiple chemicals that are measured
+// separately, e.g. part A and B of Pyrocat-HD.
+type PartsChem interface {
+	Chem
+	Parts() []Chem
+}
+
 type Simple struct {
 	density float64
 	ratio   float64
@@ -19,41 +20,74 @@ type Simple struct {
 func (s Simple) Density() float64         { return s.density }
 func (s Simple) Volume(v float64) float64 { return s.ratio * v }
 
-func ScaleParts(scale string) (values [2]int) {
-	p := strings.FieldsFunc(scale, func(r rune) bool {
-		return r == ':' || r == '/' || r == '+'
-	})
-	if len(p) > 2 {
-		return
+// Multi is a PartsChem of which each part is a Simple.
+type Multi struct {
+	parts []Chem
+}
+
+// Density returns the density of all parts mixed together.
+func (m Multi) Density() float64 {
+	var vol, weight float64
+	for _, p := range m.parts {
+		vol += p.Volume(1)
+		weight += p.Volume(1) * p.Density()
 	}
-	for i, n := range p {
-		l, err := strconv.Atoi(n)
-		if err != nil {
-			panic(err)
-		}
-		if i > 1 {
-			fmt.Println(scale)
-		}
-		values[i] = l
+	if vol == 0 {
+		return 0
 	}
+	return weight / vol
+}
 
-	return
+func (m Multi) Volume(v float64) float64 {
+	var vol float64
+	for _, p := range m.parts {
+		vol += p.Volume(v)
+	}
+	return vol
 }
 
-func ScaleRatio(scale string) float64 {
-	v := ScaleParts(scale)
+func (m Multi) Parts() []Chem { return m.parts }
 
-	return float64(v[0]) / float64(v[1]+v[0])
+// ScaleParts parses the chart dilution scale like ParseChartRatio, the
+// returned Ratio is not Valid if scale could not be parsed.
+func ScaleParts(scale string) Ratio {
+	r, _ := ParseChartRatio(scale)
+	return r
 }
 
-func ScaleString(values [2]int) string {
-	return fmt.Sprintf("%d+%d", values[0], values[1])
+func ScaleRatio(scale string) float64 {
+	return ScaleParts(scale).Fraction()
 }
 
 func NewChem(density, ratio float64) Chem {
 	return Simple{density, ratio}
 }
 
+// NewRatioChem returns the Chem for ratio, a Multi if it has more than one
+// chemical. densities are those of each chemical, the last one is used for
+// the remaining chemicals.
+func NewRatioChem(ratio Ratio, densities ...float64) Chem {
+	chem := ratio.Chem()
+	total := ratio.Total()
+	parts := make([]Chem, len(chem))
+	for i, p := range chem {
+		var density float64
+		switch {
+		case i < len(densities):
+			density = densities[i]
+		case len(densities) != 0:
+			density = densities[len(densities)-1]
+		}
+		parts[i] = NewChem(density, p/total)
+	}
+
+	if len(parts) == 1 {
+		return parts[0]
+	}
+
+	return Multi{parts}
+}
+
 type Stock struct {
 	Name string
 }
@@ -0,0 +1,165 @@
+package devchart
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+)
+
+// bundleVersion is bumped whenever the bundle format changes incompatibly.
+const bundleVersion = 1
+
+// Bundle holds all cached data so it can be copied to another cache.
+type Bundle struct {
+	Version    int
+	Created    time.Time
+	Options    *BundleOptions
+	Developers []BundleDeveloper
+	Films      []BundleFilm
+	Notes      []BundleNote
+}
+
+type BundleOptions struct {
+	Fetched time.Time
+	Options Options
+}
+
+type BundleDeveloper struct {
+	Name    string
+	Fetched time.Time
+	Entries []Entry
+}
+
+type BundleFilm struct {
+	Name    string
+	Fetched time.Time
+	Entries []Entry
+}
+
+type BundleNote struct {
+	URL     string
+	Fetched time.Time
+	Notes   []string
+	Err     string `json:",omitempty"`
+}
+
+// Export writes all cached developers, films, the options and the note cache
+// as a JSON encoded Bundle to w.
+func (c *Cache) Export(w io.Writer) error {
+	b := Bundle{
+		Version:    bundleVersion,
+		Created:    time.Now(),
+		Developers: make([]BundleDeveloper, 0),
+		Films:      make([]BundleFilm, 0),
+		Notes:      make([]BundleNote, 0),
+	}
+
+	var opts record[Options]
+	if err := readRecord(c.optionsPath(), &opts); err == nil {
+		b.Options = &BundleOptions{Fetched: opts.Fetched, Options: opts.Data}
+		for _, dev := range opts.Data.Developers {
+			var rec record[[]Entry]
+			if err := readRecord(c.devPath(dev), &rec); err != nil {
+				continue
+			}
+			b.Developers = append(b.Developers, BundleDeveloper{
+				Name:    dev,
+				Fetched: rec.Fetched,
+				Entries: rec.Data,
+			})
+		}
+		for _, film := range opts.Data.Stocks {
+			var rec record[[]Entry]
+			if err := readRecord(c.filmPath(film), &rec); err != nil {
+				continue
+			}
+			b.Films = append(b.Films, BundleFilm{
+				Name:    film,
+				Fetched: rec.Fetched,
+				Entries: rec.Data,
+			})
+		}
+	}
+
+	files, err := os.ReadDir(filepath.Join(c.dir, "notes"))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, f := range files {
+		if filepath.Ext(f.Name()) != "" {
+			continue
+		}
+		var rec record[note]
+		if err := readRecord(filepath.Join(c.dir, "notes", f.Name()), &rec); err != nil {
+			continue
+		}
+		b.Notes = append(b.Notes, BundleNote{
+			URL:     rec.Data.URL,
+			Fetched: rec.Fetched,
+			Notes:   rec.Data.Notes,
+			Err:     rec.Data.Err,
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(b)
+}
+
+// Import reads a Bundle written by Export and stores its data in the cache.
+// Data that is cached more recently than the data in the bundle is kept.
+func (c *Cache) Import(r io.Reader) error {
+	var b Bundle
+	if err := json.NewDecoder(r).Decode(&b); err != nil {
+		return err
+	}
+	if b.Version != bundleVersion {
+		return fmt.Errorf("unsupported bundle version %d", b.Version)
+	}
+
+	if b.Options != nil {
+		err := importRecord(c.optionsPath(), b.Options.Fetched, b.Options.Options)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, d := range b.Developers {
+		if err := importRecord(c.devPath(d.Name), d.Fetched, d.Entries); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range b.Films {
+		if err := importRecord(c.filmPath(f.Name), f.Fetched, f.Entries); err != nil {
+			return err
+		}
+	}
+
+	for _, n := range b.Notes {
+		data := note{URL: n.URL, Notes: n.Notes, Err: n.Err}
+		if err := importRecord(c.notePath(n.URL), n.Fetched, data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func importRecord[T any](file string, fetched time.Time, data T) error {
+	var current record[T]
+	if readRecord(file, &current) == nil {
+		if current.Fetched.After(fetched) || reflect.DeepEqual(current.Data, data) {
+			return nil
+		}
+		if err := keepPrevious(file); err != nil {
+			return err
+		}
+	}
+
+	return writeRecord(file, record[T]{Version: cacheVersion, Fetched: fetched, Data: data})
+}
@@ -0,0 +1,74 @@
+package devchart
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBundle(t *testing.T) {
+	src := NewCache(&Client{}, t.TempDir(), 0)
+	fetched := time.Now().Add(-time.Hour).Truncate(time.Second)
+	opts := Options{Developers: []string{"Rodinal"}, Stocks: []string{"Kentmere 400"}}
+	entries := []Entry{{
+		Name:      "Kentmere 400",
+		Developer: "Rodinal",
+		Dilution:  "1+25",
+		ISO:       "400",
+		T135:      ParseTime("8"),
+		NotesURL:  "/devchart.php?note=1",
+	}}
+	for file, data := range map[string]any{
+		src.optionsPath():                 opts,
+		src.devPath("Rodinal"):            entries,
+		src.filmPath("Kentmere 400"):      entries,
+		src.notePath(entries[0].NotesURL): note{URL: entries[0].NotesURL, Notes: []string{"Agitate once per minute."}},
+	} {
+		var err error
+		switch d := data.(type) {
+		case Options:
+			err = writeRecord(file, record[Options]{Version: cacheVersion, Fetched: fetched, Data: d})
+		case []Entry:
+			err = writeRecord(file, record[[]Entry]{Version: cacheVersion, Fetched: fetched, Data: d})
+		case note:
+			err = writeRecord(file, record[note]{Version: cacheVersion, Fetched: fetched, Data: d})
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := src.Export(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := NewCache(&Client{}, t.TempDir(), 0)
+	dst.SetOffline(true)
+	if err := dst.Import(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if got, err := dst.Options(ctx); err != nil || !reflect.DeepEqual(got, opts) {
+		t.Errorf("options: got %+v, %v", got, err)
+	}
+	for what, get := range map[string]func() ([]Entry, error){
+		"developer": func() ([]Entry, error) { return dst.Developer(ctx, "Rodinal") },
+		"film":      func() ([]Entry, error) { return dst.Film(ctx, "Kentmere 400") },
+	} {
+		got, err := get()
+		if err != nil {
+			t.Errorf("%s: %v", what, err)
+			continue
+		}
+		if len(got) != 1 || got[0].T135.Raw != "8" || len(got[0].Notes) != 1 {
+			t.Errorf("%s: got %+v", what, got)
+		}
+	}
+	if at, ok := dst.Cached("Rodinal"); !ok || !at.Equal(fetched) {
+		t.Errorf("developer fetched at %v, want %v", at, fetched)
+	}
+}
@@ -0,0 +1,286 @@
+package devchart
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheVersion is bumped whenever the layout of cached data changes,
+// files written by another version are considered missing.
+const cacheVersion = 4
+
+type record[T any] struct {
+	Version int
+	Fetched time.Time
+	Data    T
+}
+
+// StaleError is returned alongside cached data that expired but could not be
+// refetched.
+type StaleError struct {
+	Fetched time.Time
+	Err     error
+}
+
+func (s StaleError) Error() string {
+	return fmt.Sprintf(
+		"using stale data from %s: %s",
+		s.Fetched.Format(time.DateTime),
+		s.Err,
+	)
+}
+
+func (s StaleError) Unwrap() error { return s.Err }
+
+// NotCachedError is returned in offline mode instead of fetching data that is
+// not cached, Missing lists what would have been fetched.
+type NotCachedError struct {
+	Missing []string
+}
+
+func (n NotCachedError) Error() string {
+	return fmt.Sprintf("offline, not fetching %s", strings.Join(n.Missing, ", "))
+}
+
+// Cache stores devchart data fetched by client in dir and refetches it once
+// it is older than ttl. A ttl <= 0 means cached data never expires.
+// It is the Source of Massive Dev Chart entries.
+type Cache struct {
+	client  *Client
+	dir     string
+	ttl     time.Duration
+	dils    Dilutions
+	offline bool
+
+	noteLocks sync.Map
+	// refreshedNotes holds the urls of the notes refetched by Refresh.
+	refreshedNotes sync.Map
+}
+
+func NewCache(client *Client, dir string, ttl time.Duration) *Cache {
+	return &Cache{client: client, dir: dir, ttl: ttl, dils: DefaultDilutions}
+}
+
+// SetDilutions sets the dilution codes that are resolved in all returned
+// entries, DefaultDilutions by default.
+func (c *Cache) SetDilutions(d Dilutions) { c.dils = d }
+
+// SetOffline sets whether c is limited to cached data, regardless of its age.
+// Anything that would be fetched results in a NotCachedError instead.
+func (c *Cache) SetOffline(offline bool) { c.offline = offline }
+
+// Options returns the cached list of developers and stocks, fetching it if
+// it is missing or expired.
+func (c *Cache) Options(ctx context.Context) (Options, error) {
+	return load(c, c.optionsPath(), "the developer and stock lists", false, func() (Options, error) {
+		return c.client.Options(ctx)
+	})
+}
+
+// RefreshOptions refetches the list of developers and stocks.
+func (c *Cache) RefreshOptions(ctx context.Context) (Options, error) {
+	return load(c, c.optionsPath(), "the developer and stock lists", true, func() (Options, error) {
+		return c.client.Options(ctx)
+	})
+}
+
+// Developer returns the cached entries for dev, fetching them if they are
+// missing or expired.
+// A NotesError is returned alongside the entries if some of their notes could
+// not be fetched.
+func (c *Cache) Developer(ctx context.Context, dev string) ([]Entry, error) {
+	return c.developer(ctx, dev, false)
+}
+
+// Refresh refetches the entries for dev and their notes, notes that were
+// already refetched for another developer are not fetched again.
+func (c *Cache) Refresh(ctx context.Context, dev string) ([]Entry, error) {
+	return c.developer(ctx, dev, true)
+}
+
+func (c *Cache) developer(ctx context.Context, dev string, force bool) ([]Entry, error) {
+	entries, err := load(c, c.devPath(dev), fmt.Sprintf("developer '%s'", dev), force, func() ([]Entry, error) {
+		entries, err := c.client.Developer(ctx, dev)
+		if err != nil {
+			return entries, err
+		}
+		if err := c.fetchNotes(ctx, entries, force); err != nil {
+			return entries, err
+		}
+		_ = c.readNotes(entries)
+		return entries, nil
+	})
+	if nerr := c.readNotes(entries); err == nil {
+		err = nerr
+	}
+	c.dils.Apply(dev, entries)
+	setSource(entries, SourceMDC)
+
+	return entries, err
+}
+
+// Film returns the cached entries of all developers for film, fetching them
+// if they are missing or expired. Like Developer a NotesError is returned
+// alongside the entries if some of their notes could not be fetched.
+func (c *Cache) Film(ctx context.Context, film string) ([]Entry, error) {
+	entries, err := load(c, c.filmPath(film), fmt.Sprintf("stock '%s'", film), false, func() ([]Entry, error) {
+		entries, err := c.client.Film(ctx, film)
+		if err != nil {
+			return entries, err
+		}
+		if err := c.fetchNotes(ctx, entries, false); err != nil {
+			return entries, err
+		}
+		_ = c.readNotes(entries)
+		return entries, nil
+	})
+	if nerr := c.readNotes(entries); err == nil {
+		err = nerr
+	}
+	c.dils.Apply("", entries)
+	setSource(entries, SourceMDC)
+
+	return entries, err
+}
+
+// Peek returns the cached entries for dev regardless of their age, it never
+// fetches anything.
+func (c *Cache) Peek(dev string) ([]Entry, bool) {
+	var rec record[[]Entry]
+	if err := readRecord(c.devPath(dev), &rec); err != nil {
+		return nil, false
+	}
+	_ = c.readNotes(rec.Data)
+	c.dils.Apply(dev, rec.Data)
+	setSource(rec.Data, SourceMDC)
+
+	return rec.Data, true
+}
+
+// Previous returns the entries for dev as they were cached before the last
+// refresh that changed them and when they were fetched.
+// Unlike Peek, notes are as they were at that time.
+func (c *Cache) Previous(dev string) ([]Entry, time.Time, bool) {
+	var rec record[[]Entry]
+	if err := readRecord(c.devPath(dev)+".prev", &rec); err != nil {
+		return nil, time.Time{}, false
+	}
+	c.dils.Apply(dev, rec.Data)
+	setSource(rec.Data, SourceMDC)
+
+	return rec.Data, rec.Fetched, true
+}
+
+// Cached reports whether entries for dev are cached and when they were
+// fetched.
+func (c *Cache) Cached(dev string) (time.Time, bool) {
+	var rec record[[]Entry]
+	if err := readRecord(c.devPath(dev), &rec); err != nil {
+		return time.Time{}, false
+	}
+	return rec.Fetched, true
+}
+
+func (c *Cache) optionsPath() string { return filepath.Join(c.dir, "options") }
+
+func (c *Cache) devPath(dev string) string { return c.path("dev", dev) }
+
+func (c *Cache) filmPath(film string) string { return c.path("film", film) }
+
+func (c *Cache) path(prefix, name string) string {
+	filename := strings.Trim(safepathRE.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	sum := sha1.Sum([]byte(name))
+	filename = fmt.Sprintf("%s-%s-%s", prefix, filename, hex.EncodeToString(sum[:4]))
+	return filepath.Join(c.dir, filename)
+}
+
+func (c *Cache) expired(fetched time.Time) bool {
+	return c.ttl > 0 && time.Since(fetched) >= c.ttl
+}
+
+// load returns the data cached in file or fetches it, what describes the data
+// for a NotCachedError.
+func load[T any](c *Cache, file, what string, force bool, fetch func() (T, error)) (T, error) {
+	var rec record[T]
+	cached := readRecord(file, &rec) == nil
+	if cached && !force && (c.offline || !c.expired(rec.Fetched)) {
+		return rec.Data, nil
+	}
+	if c.offline {
+		return rec.Data, NotCachedError{Missing: []string{what}}
+	}
+
+	data, err := fetch()
+	if err != nil {
+		if cached {
+			return rec.Data, StaleError{Fetched: rec.Fetched, Err: err}
+		}
+		return data, err
+	}
+
+	if cached && !reflect.DeepEqual(rec.Data, data) {
+		if err := keepPrevious(file); err != nil {
+			return data, err
+		}
+	}
+
+	rec = record[T]{Version: cacheVersion, Fetched: time.Now(), Data: data}
+	return data, writeRecord(file, rec)
+}
+
+// keepPrevious moves file aside so its data is kept as the previous version
+// when it is about to be replaced by different data.
+func keepPrevious(file string) error {
+	return os.Rename(file, file+".prev")
+}
+
+func readRecord[T any](file string, rec *record[T]) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := gob.NewDecoder(f).Decode(rec); err != nil {
+		return err
+	}
+	if rec.Version != cacheVersion {
+		return fmt.Errorf("cache version mismatch: %d != %d", rec.Version, cacheVersion)
+	}
+
+	return nil
+}
+
+func writeRecord[T any](file string, rec record[T]) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+
+	enc := gob.NewEncoder(f)
+	if err := enc.Encode(rec); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	return os.Rename(tmp, file)
+}
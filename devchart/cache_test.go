@@ -0,0 +1,112 @@
+package devchart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCacheNoteFailure(t *testing.T) {
+	var failNotes atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("note") != "" {
+			if failNotes.Load() {
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprint(w, `<table class="notenote"><tr><td>Note [1]</td></tr><tr><td>Agitate once per minute.</td></tr></table>`)
+			return
+		}
+		fmt.Fprint(w, `<table>
+<tr><th>Film</th><th>Developer</th><th>Dilution</th><th>ASA/ISO</th><th>35mm</th><th>Notes</th></tr>
+<tr><td>Kentmere 400</td><td>Rodinal</td><td>1+25</td><td>400</td><td>8</td><td><a href="/devchart.php?note=1">[1]</a></td></tr>
+</table>`)
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(srv.URL, srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewCache(client, t.TempDir(), 0)
+	ctx := context.Background()
+
+	entries, err := c.Developer(ctx, "Rodinal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || len(entries[0].Notes) != 1 {
+		t.Fatalf("got %+v, want one entry with a note", entries)
+	}
+
+	failNotes.Store(true)
+	entries, err = c.Refresh(ctx, "Rodinal")
+	if !errors.As(err, &NotesError{}) {
+		t.Fatalf("got %v, want a NotesError", err)
+	}
+	if len(entries) != 1 || len(entries[0].Notes) != 1 {
+		t.Errorf("got %+v, want the previously fetched note", entries)
+	}
+	if _, err := os.Stat(c.devPath("Rodinal") + ".prev"); !os.IsNotExist(err) {
+		t.Errorf("a failed note fetch replaced the previous version: %v", err)
+	}
+
+	// the failure is reported until the notes are fetched again.
+	if _, err := c.Developer(ctx, "Rodinal"); !errors.As(err, &NotesError{}) {
+		t.Errorf("got %v, want a NotesError", err)
+	}
+
+	failNotes.Store(false)
+	if _, err := c.Refresh(ctx, "Rodinal"); err != nil {
+		t.Errorf("refresh after the failure: %v", err)
+	}
+	if _, err := c.Developer(ctx, "Rodinal"); err != nil {
+		t.Errorf("got %v after a successful refresh", err)
+	}
+}
+
+func TestCacheRefreshSharedNotes(t *testing.T) {
+	var noteFetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("note") != "" {
+			noteFetches.Add(1)
+			fmt.Fprint(w, `<table class="notenote"><tr><td>Note [1]</td></tr><tr><td>Agitate once per minute.</td></tr></table>`)
+			return
+		}
+		fmt.Fprint(w, `<table>
+<tr><th>Film</th><th>Developer</th><th>Dilution</th><th>ASA/ISO</th><th>35mm</th><th>Notes</th></tr>
+<tr><td>Kentmere 400</td><td>Rodinal</td><td>1+25</td><td>400</td><td>8</td><td><a href="/devchart.php?note=1">[1]</a></td></tr>
+</table>`)
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(srv.URL, srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	c := NewCache(client, dir, 0)
+	for _, dev := range []string{"Rodinal", "Adonal", "Rodinal"} {
+		if _, err := c.Refresh(ctx, dev); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := noteFetches.Load(); n != 1 {
+		t.Errorf("fetched a shared note %d times in one refresh, want 1", n)
+	}
+
+	c = NewCache(client, dir, 0)
+	if _, err := c.Refresh(ctx, "Rodinal"); err != nil {
+		t.Fatal(err)
+	}
+	if n := noteFetches.Load(); n != 2 {
+		t.Errorf("got %d note fetches, want the note refetched by a new refresh", n)
+	}
+}
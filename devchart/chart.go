@@ -0,0 +1,89 @@
+package devchart
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+)
+
+// SourceLocal is the Source of entries in a user maintained Chart.
+const SourceLocal = "local"
+
+// Chart is a list of entries maintained by hand, e.g. a user's own chart or a
+// manufacturer's datasheet.
+type Chart struct {
+	Entries []Entry
+}
+
+// ReadChart reads a JSON encoded Chart, the Source of all entries is set to
+// source.
+func ReadChart(r io.Reader, source string) (Chart, error) {
+	var c Chart
+	if err := json.NewDecoder(r).Decode(&c); err != nil {
+		return c, err
+	}
+	setSource(c.Entries, source)
+
+	return c, nil
+}
+
+// Write writes c JSON encoded to w.
+func (c Chart) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(c)
+}
+
+// Developers returns the developers of all entries.
+func (c Chart) Developers(ctx context.Context) ([]string, error) {
+	return c.names(func(e Entry) string { return e.Developer }), nil
+}
+
+// Stocks returns the stocks of all entries.
+func (c Chart) Stocks(ctx context.Context) ([]string, error) {
+	return c.names(func(e Entry) string { return e.Name }), nil
+}
+
+// Developer returns the entries of dev.
+func (c Chart) Developer(ctx context.Context, dev string) ([]Entry, error) {
+	entries := c.filter(func(e Entry) bool { return sameName(e.Developer, dev) })
+	if len(entries) == 0 {
+		return nil, NotExistsError{kind: "developer", name: dev}
+	}
+	return entries, nil
+}
+
+// Film returns the entries of film.
+func (c Chart) Film(ctx context.Context, film string) ([]Entry, error) {
+	entries := c.filter(func(e Entry) bool { return sameName(e.Name, film) })
+	if len(entries) == 0 {
+		return nil, NotExistsError{kind: "stock", name: film}
+	}
+	return entries, nil
+}
+
+func (c Chart) names(name func(Entry) string) []string {
+	l := make([]string, 0)
+	seen := make(map[string]struct{})
+	for _, e := range c.Entries {
+		n := name(e)
+		if _, ok := seen[n]; ok {
+			continue
+		}
+		seen[n] = struct{}{}
+		l = append(l, n)
+	}
+
+	return l
+}
+
+func (c Chart) filter(match func(Entry) bool) []Entry {
+	l := make([]Entry, 0)
+	for _, e := range c.Entries {
+		if match(e) {
+			l = append(l, e)
+		}
+	}
+
+	return l
+}
@@ -0,0 +1,134 @@
+package devchart
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// DefaultURL is the location of the Massive Dev Chart.
+const DefaultURL = "https://www.digitaltruth.com"
+
+var defaultURL, _ = url.Parse(DefaultURL)
+
+// DefaultClient is used by Get and GetOptions.
+var DefaultClient = &Client{}
+
+// Client scrapes the Massive Dev Chart.
+// The zero value uses DefaultURL and http.DefaultClient.
+type Client struct {
+	// BaseURL of the Massive Dev Chart (or a mirror of it).
+	BaseURL *url.URL
+	// HTTP is the client used to perform requests.
+	HTTP *http.Client
+	// UserAgent, if not empty, is sent with each request.
+	UserAgent string
+	// RPS limits the number of requests per second, <= 0 means no limit.
+	RPS float64
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// NewClient creates a client for the Massive Dev Chart hosted at baseURL.
+func NewClient(baseURL string, client *http.Client) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{BaseURL: u, HTTP: client}, nil
+}
+
+// Options fetches the list of developers and stocks.
+func (c *Client) Options(ctx context.Context) (Options, error) {
+	var o Options
+	var err error
+	o.Developers, o.Stocks, err = c.getOptions(ctx)
+	return o, err
+}
+
+// Developer fetches all entries for the developer with the given name.
+// Notes are not fetched, see Notes.
+func (c *Client) Developer(ctx context.Context, name string) ([]Entry, error) {
+	return c.search(ctx, "", name)
+}
+
+// Film fetches the entries of all developers for the film stock with the
+// given name. Notes are not fetched, see Notes.
+func (c *Client) Film(ctx context.Context, name string) ([]Entry, error) {
+	return c.search(ctx, name, "")
+}
+
+// Notes fetches the notes page at an Entry's NotesURL.
+func (c *Client) Notes(ctx context.Context, url string) ([]string, error) {
+	return c.getNotes(ctx, url)
+}
+
+func (c *Client) baseURL() url.URL {
+	if c.BaseURL == nil {
+		return *defaultURL
+	}
+	return *c.BaseURL
+}
+
+// wait blocks until the next request is allowed by RPS.
+func (c *Client) wait(ctx context.Context) error {
+	if c.RPS <= 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	at := time.Now()
+	if c.next.After(at) {
+		at = c.next
+	}
+	c.next = at.Add(time.Duration(float64(time.Second) / c.RPS))
+	c.mu.Unlock()
+
+	d := time.Until(at)
+	if d <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+func (c *Client) get(ctx context.Context, u string) (*http.Response, error) {
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		return nil, HTTPError{URL: u, StatusCode: res.StatusCode, Status: res.Status}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,94 @@
+package devchart
+
+import (
+	"reflect"
+	"slices"
+)
+
+// Change describes an entry that was added (Old is nil), removed (New is nil)
+// or changed between two versions of a table.
+type Change struct {
+	Old *Entry
+	New *Entry
+}
+
+type diffKey struct{ name, developer, iso string }
+
+// Diff returns the changes between the old and new version of a table.
+// Entries are matched by film, developer and ISO. If several entries share
+// those, entries with the same dilution and temperature are matched first.
+// Notes are compared as a set, entries whose notes are only listed in another
+// order are unchanged.
+func Diff(old, new []Entry) []Change {
+	group := func(entries []Entry) (map[diffKey][]*Entry, []diffKey) {
+		m := make(map[diffKey][]*Entry)
+		keys := make([]diffKey, 0)
+		for i := range entries {
+			e := &entries[i]
+			k := diffKey{e.Name, e.Developer, e.ISO}
+			if _, ok := m[k]; !ok {
+				keys = append(keys, k)
+			}
+			m[k] = append(m[k], e)
+		}
+		return m, keys
+	}
+
+	oldGroups, oldKeys := group(old)
+	newGroups, newKeys := group(new)
+	for _, k := range oldKeys {
+		if _, ok := newGroups[k]; !ok {
+			newKeys = append(newKeys, k)
+		}
+	}
+
+	changes := make([]Change, 0)
+	for _, k := range newKeys {
+		o, n := oldGroups[k], newGroups[k]
+		match := func(eq func(a, b *Entry) bool, changed bool) {
+			for i := 0; i < len(o); i++ {
+				for j := 0; j < len(n); j++ {
+					if !eq(o[i], n[j]) {
+						continue
+					}
+					if changed {
+						changes = append(changes, Change{Old: o[i], New: n[j]})
+					}
+					o = append(o[:i], o[i+1:]...)
+					n = append(n[:j], n[j+1:]...)
+					i--
+					break
+				}
+			}
+		}
+
+		match(sameEntry, false)
+		match(func(a, b *Entry) bool { return a.Dilution == b.Dilution && a.Temp == b.Temp }, true)
+		match(func(a, b *Entry) bool { return a.Dilution == b.Dilution }, true)
+		match(func(a, b *Entry) bool { return true }, true)
+
+		for _, e := range o {
+			changes = append(changes, Change{Old: e})
+		}
+		for _, e := range n {
+			changes = append(changes, Change{New: e})
+		}
+	}
+
+	return changes
+}
+
+func sameEntry(a, b *Entry) bool {
+	notes := func(e *Entry) []string {
+		n := slices.Clone(e.Notes)
+		slices.Sort(n)
+		return slices.Compact(n)
+	}
+	if !slices.Equal(notes(a), notes(b)) {
+		return false
+	}
+
+	ca, cb := *a, *b
+	ca.Notes, cb.Notes = nil, nil
+	return reflect.DeepEqual(ca, cb)
+}
@@ -0,0 +1,89 @@
+package devchart
+
+import "testing"
+
+func TestDiff(t *testing.T) {
+	e := func(name, dil, iso string, temp float64, t135 string) Entry {
+		return Entry{Name: name, Developer: "Rodinal", Dilution: dil, ISO: iso, Temp: temp, T135: ParseTime(t135)}
+	}
+
+	old := []Entry{
+		e("Kentmere 400", "1+25", "400", 20, "8"),
+		e("Kentmere 400", "1+50", "400", 20, "13"),
+		e("Ilford HP5+", "1+25", "400", 20, "6"),
+		e("Ilford HP5+", "1+50", "800", 20, "11"),
+		e("Ilford FP4+", "1+25", "125", 20, "9"),
+	}
+	new := []Entry{
+		// unchanged, but listed in another order.
+		e("Kentmere 400", "1+50", "400", 20, "13"),
+		// time changed, matched by dilution and temperature.
+		e("Kentmere 400", "1+25", "400", 20, "9"),
+		// temperature changed, matched by dilution.
+		e("Ilford HP5+", "1+25", "400", 24, "6"),
+		// dilution changed, matched by film, developer and iso only.
+		e("Ilford HP5+", "1+100", "800", 20, "11"),
+		e("Ilford Delta 100", "1+50", "100", 20, "11"),
+	}
+
+	type change struct{ old, new string }
+	desc := func(e *Entry) string {
+		if e == nil {
+			return ""
+		}
+		return e.Name + " " + e.Dilution + " " + e.T135.Raw
+	}
+	want := []change{
+		{"Kentmere 400 1+25 8", "Kentmere 400 1+25 9"},
+		{"Ilford HP5+ 1+25 6", "Ilford HP5+ 1+25 6"},
+		{"Ilford HP5+ 1+50 11", "Ilford HP5+ 1+100 11"},
+		{"", "Ilford Delta 100 1+50 11"},
+		{"Ilford FP4+ 1+25 9", ""},
+	}
+
+	changes := Diff(old, new)
+	if len(changes) != len(want) {
+		for _, c := range changes {
+			t.Logf("%q -> %q", desc(c.Old), desc(c.New))
+		}
+		t.Fatalf("got %d changes, want %d", len(changes), len(want))
+	}
+	for i, c := range changes {
+		if got := (change{desc(c.Old), desc(c.New)}); got != want[i] {
+			t.Errorf("change %d: got %q, want %q", i, got, want[i])
+		}
+	}
+}
+
+func TestDiffDuplicates(t *testing.T) {
+	a := Entry{Name: "Kentmere 400", Developer: "Rodinal", Dilution: "1+25", ISO: "400", Temp: 20, T135: ParseTime("8")}
+	b := a
+	b.Dilution, b.T135 = "1+50", ParseTime("13")
+	c := b
+	c.T135 = ParseTime("14")
+
+	// a is unchanged, b changed into c: a must not be paired with c.
+	changes := Diff([]Entry{b, a}, []Entry{a, c})
+	if len(changes) != 1 {
+		t.Fatalf("got %d changes, want 1", len(changes))
+	}
+	if changes[0].Old.T135.Raw != "13" || changes[0].New.T135.Raw != "14" {
+		t.Errorf("got %s -> %s, want 13 -> 14", changes[0].Old.T135, changes[0].New.T135)
+	}
+}
+
+func TestDiffNotesOrder(t *testing.T) {
+	a := Entry{Name: "Kentmere 400", Developer: "Rodinal", Dilution: "1+25", ISO: "400", NotesURL: "/note=1"}
+	a.Notes = []string{"Agitate once per minute.", "Presoak."}
+	b := a
+	b.Notes = []string{"Presoak.", "Agitate once per minute."}
+
+	if changes := Diff([]Entry{a}, []Entry{b}); len(changes) != 0 {
+		t.Errorf("got %d changes for reordered notes, want 0", len(changes))
+	}
+
+	b.Notes = []string{"Presoak."}
+	if changes := Diff([]Entry{a}, []Entry{b}); len(changes) != 1 {
+		t.Errorf("got %d changes for a removed note, want 1", len(changes))
+	}
+}
@@ -0,0 +1,141 @@
+package devchart
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// AllDevelopers is the prefix that matches every developer.
+const AllDevelopers = "*"
+
+// Dilutions maps developer name prefixes to dilution codes and the ratio
+// they represent, e.g.: Dilutions{"HC-110": {"B": "1+31"}}.
+type Dilutions map[string]map[string]string
+
+// DefaultDilutions are the dilution codes used in the Massive Dev Chart.
+var DefaultDilutions = Dilutions{
+	AllDevelopers: {
+		"stock": "1+0",
+	},
+	"HC-110": {
+		"A": "1+15",
+		"B": "1+31",
+		"C": "1+19",
+		"D": "1+39",
+		"E": "1+47",
+		"F": "1+79",
+		"G": "1+119",
+		"H": "1+63",
+		"J": "1+150",
+	},
+	"Ilfotec": {
+		"A": "1+15",
+		"B": "1+31",
+		"C": "1+19",
+		"D": "1+39",
+		"E": "1+47",
+		"F": "1+79",
+		"G": "1+119",
+		"H": "1+63",
+		"J": "1+150",
+	},
+}
+
+// ReadDilutions reads dilution codes, one per line in the form of
+// '<developer prefix> <code> <ratio>' (e.g. 'HC-110 B 1+31').
+// A prefix of * matches all developers, empty lines and lines starting with
+// # are ignored.
+func ReadDilutions(r io.Reader) (Dilutions, error) {
+	d := make(Dilutions)
+	scan := bufio.NewScanner(r)
+	for scan.Scan() {
+		text := strings.TrimSpace(scan.Text())
+		if text == "" || text[0] == '#' {
+			continue
+		}
+
+		f := strings.Fields(text)
+		if len(f) < 3 {
+			return d, fmt.Errorf("invalid dilution line '%s'", text)
+		}
+
+		prefix := strings.Join(f[:len(f)-2], " ")
+		if d[prefix] == nil {
+			d[prefix] = make(map[string]string)
+		}
+		d[prefix][f[len(f)-2]] = f[len(f)-1]
+	}
+
+	return d, scan.Err()
+}
+
+// Merge returns the union of d and o, codes in o take precedence.
+func (d Dilutions) Merge(o Dilutions) Dilutions {
+	n := make(Dilutions, len(d)+len(o))
+	for _, src := range []Dilutions{d, o} {
+		for prefix, codes := range src {
+			if n[prefix] == nil {
+				n[prefix] = make(map[string]string, len(codes))
+			}
+			for code, ratio := range codes {
+				n[prefix][code] = ratio
+			}
+		}
+	}
+
+	return n
+}
+
+// Resolve returns the ratio for the dilution code of developer dev.
+// The longest matching prefix wins, prefixes of equal length are tried in
+// alphabetical order.
+func (d Dilutions) Resolve(dev, code string) (string, bool) {
+	match := func(prefix string) string {
+		if prefix == AllDevelopers {
+			return ""
+		}
+		return prefix
+	}
+
+	prefixes := make([]string, 0, len(d))
+	for prefix := range d {
+		if strings.HasPrefix(dev, match(prefix)) {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	slices.SortFunc(prefixes, func(a, b string) int {
+		if n := cmp.Compare(len(match(b)), len(match(a))); n != 0 {
+			return n
+		}
+		return strings.Compare(a, b)
+	})
+
+	for _, prefix := range prefixes {
+		if ratio, ok := d[prefix][code]; ok {
+			return ratio, true
+		}
+	}
+
+	return "", false
+}
+
+// Apply replaces the dilution codes of entries by their ratio and stores the
+// code in DilutionCode. Prefixes are matched against dev, or if empty against
+// each entry's Developer.
+func (d Dilutions) Apply(dev string, entries []Entry) {
+	for i := range entries {
+		e := &entries[i]
+		name := dev
+		if name == "" {
+			name = e.Developer
+		}
+		if ratio, ok := d.Resolve(name, e.Dilution); ok {
+			e.DilutionCode = e.Dilution
+			e.Dilution = ratio
+		}
+	}
+}
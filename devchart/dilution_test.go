@@ -0,0 +1,62 @@
+package devchart
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDilutionsResolve(t *testing.T) {
+	custom, err := ReadDilutions(strings.NewReader(`
+# comment
+HC-110 B 1+30
+HC-110 Dilution B 1+29
+* X 1+9
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := DefaultDilutions.Merge(custom)
+
+	tests := []struct {
+		dev, code string
+		want      string
+		ok        bool
+	}{
+		{"HC-110", "B", "1+30", true},
+		{"HC-110 (Dilution B)", "B", "1+30", true},
+		{"HC-110 Dilution B", "B", "1+29", true},
+		{"HC-110", "A", "1+15", true},
+		{"Ilfotec HC", "H", "1+63", true},
+		{"Rodinal", "stock", "1+0", true},
+		{"Rodinal", "X", "1+9", true},
+		{"Rodinal", "B", "", false},
+		{"HC-110", "1+31", "", false},
+		{"H", "X", "1+9", true},
+	}
+
+	for _, tt := range tests {
+		got, ok := d.Resolve(tt.dev, tt.code)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Resolve(%q, %q) = %q, %v, want %q, %v", tt.dev, tt.code, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDilutionsResolveOrder(t *testing.T) {
+	// * is shorter than any other prefix, a single character prefix wins.
+	d := Dilutions{AllDevelopers: {"B": "1+1"}, "H": {"B": "1+2"}, "X": {"B": "1+3"}}
+	for i := 0; i < 50; i++ {
+		if got, _ := d.Resolve("HC-110", "B"); got != "1+2" {
+			t.Fatalf("Resolve(HC-110, B) = %q, want 1+2", got)
+		}
+		if got, _ := d.Resolve("Rodinal", "B"); got != "1+1" {
+			t.Fatalf("Resolve(Rodinal, B) = %q, want 1+1", got)
+		}
+	}
+}
+
+func TestReadDilutionsInvalid(t *testing.T) {
+	if _, err := ReadDilutions(strings.NewReader("HC-110 1+31")); err == nil {
+		t.Error("expected an error for a line without a code")
+	}
+}
@@ -1,26 +1,18 @@
 package devchart
 
 import (
-	"crypto/sha1"
+	"context"
 	"encoding/gob"
-	"encoding/hex"
+	"errors"
 	"fmt"
-	"net/http"
 	"net/url"
-	"os"
 	"path"
-	"path/filepath"
 	"regexp"
-	"strconv"
 	"strings"
-	"time"
 
 	"golang.org/x/net/html"
 )
 
-const _burl = "https://www.digitaltruth.com"
-
-var burl, _ = url.Parse(_burl)
 var absURLRE = regexp.MustCompile(`^https?:`)
 var safepathRE = regexp.MustCompile(`[^a-z0-9\._-]+`)
 var noteRE = regexp.MustCompile(`(?i)note.*\[.*]$`)
@@ -34,12 +26,19 @@ type Entry struct {
 	Name      string
 	Developer string
 	Dilution  string
-	ISO       string
-	T135      time.Duration
-	T120      time.Duration
-	TSheet    time.Duration
-	Temp      float64
-	Notes     []string
+	// DilutionCode is the dilution as listed if it was resolved to a ratio
+	// using Dilutions (e.g. B for HC-110 1+31).
+	DilutionCode string
+	ISO          string
+	T135         Time
+	T120         Time
+	TSheet       Time
+	// Temp in degrees Celsius.
+	Temp     float64
+	Notes    []string
+	NotesURL string
+	// Source of the entry, e.g. SourceMDC or SourceLocal.
+	Source string
 }
 
 type Options struct {
@@ -47,90 +46,52 @@ type Options struct {
 	Stocks     []string
 }
 
-type NotExistsError struct{ name string }
+type NotExistsError struct{ kind, name string }
 
 func (n NotExistsError) Error() string {
-	return fmt.Sprintf("no such developer: '%s'", n.name)
+	return fmt.Sprintf("no such %s: '%s'", n.kind, n.name)
 }
 
-func GetOptions(cacheDir string) (Options, error) {
-	var o Options
-	os.MkdirAll(cacheDir, 0755)
-	cache := filepath.Join(cacheDir, "options")
+// HTTPError is returned when the Massive Dev Chart responds with a
+// non-200 status code.
+type HTTPError struct {
+	URL        string
+	StatusCode int
+	Status     string
+}
 
-	f, err := os.Open(cache)
-	if err != nil {
-		if os.IsNotExist(err) {
-			o.Developers, o.Stocks, err = getOptions()
-			if err != nil {
-				return o, err
-			}
-			tmp := cache + ".tmp"
-			f, err = os.Create(tmp)
-			if err != nil {
-				return o, err
-			}
+func (h HTTPError) Error() string {
+	return fmt.Sprintf("request to '%s' failed: %s", h.URL, h.Status)
+}
 
-			enc := gob.NewEncoder(f)
-			if err = enc.Encode(o); err != nil {
-				f.Close()
-				os.Remove(tmp)
-				return o, err
-			}
-			f.Close()
+// LayoutError is returned when a page does not contain the expected data,
+// most likely because the layout of the Massive Dev Chart changed.
+type LayoutError struct {
+	URL    string
+	Reason string
+}
 
-			return o, os.Rename(tmp, cache)
-		}
-		return o, err
+func (l LayoutError) Error() string {
+	if l.URL == "" {
+		return fmt.Sprintf("unexpected page layout: %s", l.Reason)
 	}
+	return fmt.Sprintf("unexpected page layout of '%s': %s", l.URL, l.Reason)
+}
 
-	defer f.Close()
-	dec := gob.NewDecoder(f)
-	return o, dec.Decode(&o)
+// GetOptions returns the list of developers and stocks, cached in cacheDir.
+func GetOptions(cacheDir string) (Options, error) {
+	return NewCache(DefaultClient, cacheDir, 0).Options(context.Background())
 }
 
+// Get returns all entries for dev, cached in cacheDir.
 func Get(cacheDir string, dev string) ([]Entry, error) {
-	os.MkdirAll(cacheDir, 0755)
-	filename := strings.Trim(safepathRE.ReplaceAllString(strings.ToLower(dev), "-"), "-")
-	sum := sha1.Sum([]byte(dev))
-	filename = fmt.Sprintf("dev-%s-%s", filename, hex.EncodeToString(sum[:4]))
-	cache := filepath.Join(cacheDir, filename)
-	f, err := os.Open(cache)
-	if err != nil {
-		if os.IsNotExist(err) {
-			es, err := get(dev)
-			if err != nil {
-				return es, err
-			}
-			tmp := cache + ".tmp"
-			f, err = os.Create(tmp)
-			if err != nil {
-				return nil, err
-			}
-
-			enc := gob.NewEncoder(f)
-			if err := enc.Encode(es); err != nil {
-				f.Close()
-				os.Remove(tmp)
-				return nil, err
-			}
-			f.Close()
-
-			return es, os.Rename(tmp, cache)
-		}
-		return nil, err
-	}
-
-	defer f.Close()
-	dec := gob.NewDecoder(f)
-	list := make([]Entry, 0)
-	return list, dec.Decode(&list)
+	return NewCache(DefaultClient, cacheDir, 0).Developer(context.Background(), dev)
 }
 
-func getOptions() ([]string, []string, error) {
-	u := *burl
+func (c *Client) getOptions(ctx context.Context) ([]string, []string, error) {
+	u := c.baseURL()
 	u.Path = "/devchart.php"
-	res, err := http.Get(u.String())
+	res, err := c.get(ctx, u.String())
 	if err != nil {
 		return nil, nil, err
 	}
@@ -185,174 +146,63 @@ outer:
 		}
 	}
 
+	if len(data[0]) == 0 || len(data[1]) == 0 {
+		return nil, nil, LayoutError{URL: u.String(), Reason: "no film or developer options found"}
+	}
+
 	return data[1], data[0], nil
 }
 
-func get(dev string) ([]Entry, error) {
+func (c *Client) search(ctx context.Context, film, dev string) ([]Entry, error) {
 	q := url.Values{
-		"Film":      []string{""},
+		"Film":      []string{film},
 		"Developer": []string{dev},
 		"mdc":       []string{"Search"},
 		"TempUnits": []string{"C"},
 		"TimeUnits": []string{"D"},
 	}
-	u := *burl
+	u := c.baseURL()
 	u.Path = "/devchart.php"
 	u.RawQuery = q.Encode()
-	res, err := http.Get(u.String())
+	res, err := c.get(ctx, u.String())
 	if err != nil {
 		return nil, err
 	}
-	r := res.Body
-
-	defer r.Close()
+	defer res.Body.Close()
 
-	data := make([]*[]string, 0)
-	var str string
-	var row *[]string
-	state := 0
-	z := html.NewTokenizer(r)
-outer:
-	for {
-		tt := z.Next()
-		switch {
-		case tt == html.ErrorToken:
-			break outer
-		case tt == html.StartTagToken:
-			t := z.Token()
-			switch {
-			case t.Data == "table":
-				state = 1
-			case t.Data == "tr":
-				l := 0
-				if row != nil {
-					l = len(*row)
-				}
-				_row := make([]string, 0, l)
-				row = &_row
-				data = append(data, row)
-
-				state = 2
-			case t.Data == "td":
-				state = 3
-				*row = append(*row, "")
-			case t.Data == "a" && state == 3:
-				for _, a := range t.Attr {
-					if a.Key == "href" {
-						u, err := href(a.Val)
-						if err == nil {
-							(*row)[len(*row)-1] = u.String()
-						}
-					}
-				}
-			}
-		case tt == html.EndTagToken:
-			t := z.Token()
-			switch {
-			case t.Data == "table":
-				state = 0
-			case t.Data == "tr":
-				state = 1
-			case t.Data == "td":
-				state = 2
-				(*row)[len(*row)-1] = str
-				str = ""
-			}
-		case tt == html.TextToken:
-			if state == 3 {
-				t := z.Token()
-				str = t.Data
-			}
-		}
+	entries, err := Parse(res.Body)
+	var lerr LayoutError
+	if errors.As(err, &lerr) {
+		lerr.URL = u.String()
+		return nil, lerr
 	}
-
-	dur := func(str string) time.Duration {
-		f, err := strconv.ParseFloat(str, 64)
-		if err != nil {
-			return 0
-		}
-
-		return time.Duration(float64(time.Minute) * f)
-
+	if err != nil {
+		return nil, err
 	}
 
-	entries := make([]Entry, 0, len(data))
-	for _, row := range data {
-		r := *row
-		if len(r) != 9 {
-			continue
-		}
-
-		dil := r[2]
-		if dil == "stock" {
-			dil = "1+0"
-		}
-
-		if strings.HasPrefix(dev, "HC-110") || strings.HasPrefix(dev, "Ilfotec") {
-			switch dil {
-			case "A":
-				dil = "1+15"
-			case "B":
-				dil = "1+31"
-			case "C":
-				dil = "1+19"
-			case "D":
-				dil = "1+39"
-			case "E":
-				dil = "1+47"
-			case "F":
-				dil = "1+79"
-			case "G":
-				dil = "1+119"
-			case "H":
-				dil = "1+63"
-			case "J":
-				dil = "1+150"
-			}
-		}
-
-		var temp float64 = 0
-		t := r[7]
-		if len(t) > 1 {
-			l := t[len(t)-1]
-			if l == 'c' || l == 'C' {
-				t = t[:len(t)-1]
-			}
-			temp, err = strconv.ParseFloat(t, 64)
-			if err != nil {
-				temp = 0
+	for i := range entries {
+		e := &entries[i]
+		if e.NotesURL != "" {
+			nu, err := c.href(e.NotesURL)
+			e.NotesURL = ""
+			if err == nil {
+				e.NotesURL = nu.String()
 			}
 		}
-
-		var notes []string
-		if r[8] != "" {
-			notes, _ = getNotes(r[8])
-		}
-
-		entry := Entry{
-			Name:      r[0],
-			Developer: r[1],
-			Dilution:  dil,
-			ISO:       r[3],
-			T135:      dur(r[4]),
-			T120:      dur(r[5]),
-			TSheet:    dur(r[6]),
-			Temp:      temp,
-			Notes:     notes,
-		}
-
-		entries = append(entries, entry)
 	}
 
 	if len(entries) == 0 {
-		return nil, NotExistsError{name: dev}
+		if dev == "" {
+			return nil, NotExistsError{kind: "stock", name: film}
+		}
+		return nil, NotExistsError{kind: "developer", name: dev}
 	}
 
 	return entries, nil
 }
 
-func getNotes(u string) ([]string, error) {
-	res, err := http.Get(u)
+func (c *Client) getNotes(ctx context.Context, u string) ([]string, error) {
+	res, err := c.get(ctx, u)
 	if err != nil {
 		return nil, err
 	}
@@ -360,6 +210,7 @@ func getNotes(u string) ([]string, error) {
 
 	defer r.Close()
 
+	var table bool
 	data := make([]string, 0)
 	state := 0
 	z := html.NewTokenizer(r)
@@ -377,6 +228,7 @@ outer:
 				for _, a := range t.Attr {
 					if a.Key == "class" && a.Val == "notenote" {
 						state = 1
+						table = true
 						break
 					}
 				}
@@ -403,12 +255,17 @@ outer:
 		}
 	}
 
+	if !table {
+		return nil, LayoutError{URL: u, Reason: "no notes table found"}
+	}
+
 	return data, nil
 }
 
-func href(href string) (*url.URL, error) {
+func (c *Client) href(href string) (*url.URL, error) {
+	burl := c.baseURL()
 	uri := &url.URL{}
-	*uri = *burl
+	*uri = burl
 
 	p, err := url.Parse(href)
 	if err != nil {
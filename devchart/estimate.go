@@ -0,0 +1,221 @@
+package devchart
+
+import (
+	"cmp"
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/frizinak/devcalc/dev"
+)
+
+// SourceEstimate is the Source of entries with estimated times.
+const SourceEstimate = "estimate"
+
+// Estimate is an Entry with times derived from other entries.
+type Estimate struct {
+	Entry
+	// From holds the entries the times were derived from.
+	From []Entry
+	// Extrapolated is true if any of the times lies outside the range of
+	// From.
+	Extrapolated bool
+}
+
+// ListedError is returned by EstimateISO and EstimateDilution when the iso or
+// dilution to estimate for is listed, there is nothing to estimate.
+type ListedError struct {
+	// Entries at the iso or dilution.
+	Entries []Entry
+}
+
+func (l ListedError) Error() string {
+	return "listed, nothing to estimate"
+}
+
+// estimatePoint is the average log of the times at x (e.g. stops), index holds
+// the index of each averaged entry.
+type estimatePoint struct {
+	x        float64
+	min, max float64
+	index    []int
+}
+
+// EstimateISO estimates the times at iso from entries of a single film,
+// developer, dilution and temperature.
+// Times are interpolated, or extrapolated, linearly between the logarithm of
+// the time and the number of stops (log2 of the iso) of the two nearest listed
+// speeds. Multiple entries at the same speed are averaged, two-bath times are
+// ignored.
+// A ListedError is returned if entries list iso.
+func EstimateISO(entries []Entry, iso float64) (Estimate, error) {
+	if len(entries) == 0 || iso <= 0 {
+		return Estimate{}, errors.New("nothing to estimate from")
+	}
+
+	est := Estimate{Entry: entries[0]}
+	est.ISO = strconv.FormatFloat(iso, 'f', -1, 64)
+	stops := func(e Entry) (float64, bool) {
+		iso, ok := ParseISO(e.ISO)
+		return math.Log2(iso), ok && iso > 0
+	}
+	err := estimate(&est, entries, math.Log2(iso), stops)
+	if errors.As(err, &ListedError{}) {
+		return est, err
+	}
+	if err != nil {
+		return est, errors.New("not enough speeds listed to estimate from")
+	}
+
+	return est, nil
+}
+
+// EstimateDilution estimates the times at ratio from entries of a single
+// film, developer, iso and temperature.
+// Like EstimateISO times are interpolated, or extrapolated, linearly between
+// the logarithm of the time and the logarithm of the dilution factor (e.g. 26
+// for 1+25) of the two nearest listed dilutions.
+// A ListedError is returned if entries list ratio.
+func EstimateDilution(entries []Entry, ratio dev.Ratio) (Estimate, error) {
+	if len(entries) == 0 || !ratio.Valid() || ratio.Fraction() == 0 {
+		return Estimate{}, errors.New("nothing to estimate from")
+	}
+
+	est := Estimate{Entry: entries[0]}
+	est.Dilution, est.DilutionCode = ratio.String(), ""
+	factor := func(e Entry) (float64, bool) {
+		r := dev.ScaleParts(e.Dilution)
+		return -math.Log(r.Fraction()), r.Valid() && r.Fraction() > 0
+	}
+	err := estimate(&est, entries, -math.Log(ratio.Fraction()), factor)
+	if errors.As(err, &ListedError{}) {
+		return est, err
+	}
+	if err != nil {
+		return est, errors.New("not enough dilutions listed to estimate from")
+	}
+
+	return est, nil
+}
+
+// estimate sets the times of est to those at x, where xOf returns the x of
+// each entry. A ListedError is returned if any of entries is at x.
+func estimate(est *Estimate, entries []Entry, x float64, xOf func(Entry) (float64, bool)) error {
+	var listed []Entry
+	for _, e := range entries {
+		if ex, ok := xOf(e); ok && ex == x {
+			listed = append(listed, e)
+		}
+	}
+	if len(listed) != 0 {
+		return ListedError{Entries: listed}
+	}
+
+	est.T135, est.T120, est.TSheet = Time{}, Time{}, Time{}
+	est.Notes, est.NotesURL = nil, ""
+	est.Source = SourceEstimate
+
+	used := make(map[int]struct{})
+	for _, f := range []struct {
+		dst *Time
+		get func(Entry) Time
+	}{
+		{&est.T135, func(e Entry) Time { return e.T135 }},
+		{&est.T120, func(e Entry) Time { return e.T120 }},
+		{&est.TSheet, func(e Entry) Time { return e.TSheet }},
+	} {
+		points := estimatePoints(entries, xOf, f.get)
+		span, from, extrapolated, ok := estimateSpan(points, x)
+		if !ok {
+			continue
+		}
+
+		*f.dst = newTime([]Span{span})
+		est.Extrapolated = est.Extrapolated || extrapolated
+		for _, i := range from {
+			used[i] = struct{}{}
+		}
+	}
+
+	if len(used) == 0 {
+		return errors.New("not enough entries")
+	}
+
+	for i, e := range entries {
+		if _, ok := used[i]; ok {
+			est.From = append(est.From, e)
+		}
+	}
+
+	return nil
+}
+
+// estimatePoints returns an estimatePoint for every distinct x with single
+// stage times, ordered by x.
+func estimatePoints(entries []Entry, xOf func(Entry) (float64, bool), get func(Entry) Time) []estimatePoint {
+	idx := make(map[float64][]int)
+	for i, e := range entries {
+		x, ok := xOf(e)
+		if t := get(e); !ok || len(t.Stages) != 1 {
+			continue
+		}
+		idx[x] = append(idx[x], i)
+	}
+
+	points := make([]estimatePoint, 0, len(idx))
+	for x, ix := range idx {
+		p := estimatePoint{x: x, index: ix}
+		for _, i := range ix {
+			s := get(entries[i]).Stages[0]
+			p.min += math.Log(s.Min.Seconds()) / float64(len(ix))
+			p.max += math.Log(s.Max.Seconds()) / float64(len(ix))
+		}
+		points = append(points, p)
+	}
+	slices.SortFunc(points, func(a, b estimatePoint) int {
+		return cmp.Compare(a.x, b.x)
+	})
+
+	return points
+}
+
+// estimateSpan returns the time at x stops, the index of the entries it was
+// derived from, whether it was extrapolated and whether points suffice.
+func estimateSpan(points []estimatePoint, x float64) (Span, []int, bool, bool) {
+	dur := func(v float64) time.Duration {
+		return time.Duration(math.Exp(v) * float64(time.Second)).Round(time.Second)
+	}
+
+	for _, p := range points {
+		if p.x == x {
+			return Span{dur(p.min), dur(p.max)}, p.index, false, true
+		}
+	}
+	if len(points) < 2 {
+		return Span{}, nil, false, false
+	}
+
+	i := slices.IndexFunc(points, func(p estimatePoint) bool { return p.x > x })
+	extrapolated := false
+	switch i {
+	case 0:
+		i, extrapolated = 1, true
+	case -1:
+		i, extrapolated = len(points)-1, true
+	}
+	a, b := points[i-1], points[i]
+
+	f := (x - a.x) / (b.x - a.x)
+	span := Span{
+		Min: dur(a.min + f*(b.min-a.min)),
+		Max: dur(a.max + f*(b.max-a.max)),
+	}
+	if span.Min > span.Max {
+		// ranges of different widths can cross when extrapolating.
+		span.Min, span.Max = span.Max, span.Min
+	}
+
+	return span, append(slices.Clone(a.index), b.index...), extrapolated, true
+}
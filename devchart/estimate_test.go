@@ -0,0 +1,113 @@
+package devchart
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/frizinak/devcalc/dev"
+)
+
+func TestEstimateSpan(t *testing.T) {
+	p := func(iso float64, min, max time.Duration, index ...int) estimatePoint {
+		return estimatePoint{
+			x:     math.Log2(iso),
+			min:   math.Log(min.Seconds()),
+			max:   math.Log(max.Seconds()),
+			index: index,
+		}
+	}
+	m := time.Minute
+	points := []estimatePoint{
+		p(400, 6*m, 6*m, 0),
+		p(800, 8*m, 9*m, 1, 2),
+		p(1600, 12*m, 12*m, 3),
+	}
+
+	tests := []struct {
+		iso          float64
+		points       []estimatePoint
+		want         Span
+		from         []int
+		extrapolated bool
+		ok           bool
+	}{
+		// exact match.
+		{800, points, Span{8 * m, 9 * m}, []int{1, 2}, false, true},
+		// half a stop between 400 and 800: the geometric mean.
+		{400 * math.Sqrt2, points, Span{6*m + 56*time.Second, 7*m + 21*time.Second}, []int{0, 1, 2}, false, true},
+		{1600, points, Span{12 * m, 12 * m}, []int{3}, false, true},
+		// a stop above 1600, continuing the slope of 800 to 1600.
+		{3200, points, Span{18 * m, 16 * m}, []int{1, 2, 3}, true, true},
+		// a stop below 400, continuing the slope of 400 to 800.
+		{200, points, Span{4*m + 30*time.Second, 4 * m}, []int{0, 1, 2}, true, true},
+		{400, points[:1], Span{6 * m, 6 * m}, []int{0}, false, true},
+		{800, points[:1], Span{}, nil, false, false},
+		{800, nil, Span{}, nil, false, false},
+	}
+
+	for _, tt := range tests {
+		span, from, extrapolated, ok := estimateSpan(tt.points, math.Log2(tt.iso))
+		if ok != tt.ok || extrapolated != tt.extrapolated || !slices.Equal(from, tt.from) {
+			t.Errorf("iso %v: got from %v, extrapolated %v, ok %v, want %v, %v, %v", tt.iso, from, extrapolated, ok, tt.from, tt.extrapolated, tt.ok)
+		}
+		want := tt.want
+		if want.Min > want.Max {
+			want.Min, want.Max = want.Max, want.Min
+		}
+		if span != want {
+			t.Errorf("iso %v: got %v-%v, want %v-%v", tt.iso, span.Min, span.Max, want.Min, want.Max)
+		}
+	}
+}
+
+func TestEstimateISOListed(t *testing.T) {
+	entries := []Entry{
+		{Name: "Ilford HP5+", ISO: "400", T135: ParseTime("6")},
+		{Name: "Ilford HP5+", ISO: "EI 800", T135: ParseTime("8")},
+		{Name: "Ilford HP5+", ISO: "1600", T135: ParseTime("12")},
+	}
+
+	var listed ListedError
+	if _, err := EstimateISO(entries, 800); !errors.As(err, &listed) {
+		t.Fatalf("got %v, want a ListedError", err)
+	}
+	if len(listed.Entries) != 1 || listed.Entries[0].ISO != "EI 800" {
+		t.Errorf("got listed %+v, want the EI 800 entry", listed.Entries)
+	}
+
+	est, err := EstimateISO(entries, 1200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if est.Source != SourceEstimate || est.ISO != "1200" || est.Extrapolated || len(est.From) != 2 {
+		t.Errorf("got %+v", est)
+	}
+}
+
+func TestEstimateDilution(t *testing.T) {
+	entries := []Entry{
+		{Name: "Kentmere 400", Dilution: "1+25", ISO: "400", T135: ParseTime("9")},
+		{Name: "Kentmere 400", Dilution: "1:50", ISO: "400", T135: ParseTime("13")},
+	}
+
+	est, err := EstimateDilution(entries, dev.NewRatio(1, 40))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if est.Dilution != "1+40" || est.Extrapolated || len(est.From) != 2 {
+		t.Errorf("got %+v", est)
+	}
+	if s := est.T135.Stages; len(s) != 1 || s[0].Min <= 9*time.Minute || s[0].Min >= 13*time.Minute {
+		t.Errorf("got 135 time %s, want between 9 and 13 minutes", est.T135)
+	}
+
+	if _, err := EstimateDilution(entries, dev.NewRatio(1, 50)); !errors.As(err, &ListedError{}) {
+		t.Errorf("got %v, want a ListedError", err)
+	}
+	if _, err := EstimateDilution(entries[:1], dev.NewRatio(1, 40)); err == nil {
+		t.Error("estimated from a single dilution")
+	}
+}
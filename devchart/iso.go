@@ -0,0 +1,20 @@
+package devchart
+
+import (
+	"regexp"
+	"strconv"
+)
+
+var isoRE = regexp.MustCompile(`[0-9]+(?:\.[0-9]+)?`)
+
+// ParseISO returns the film speed listed in an ISO column, e.g. 400 for
+// "400", "EI 400" or "400/27°".
+func ParseISO(s string) (float64, bool) {
+	m := isoRE.FindString(s)
+	if m == "" {
+		return 0, false
+	}
+
+	iso, err := strconv.ParseFloat(m, 64)
+	return iso, err == nil
+}
@@ -0,0 +1,148 @@
+package devchart
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const noteWorkers = 4
+
+// note is the cached result of fetching a notes page, a failed fetch is
+// recorded in Err and retried the next time a developer is fetched. Notes
+// still holds those of the last successful fetch after a failure, so a
+// failure does not show up as a changed table.
+type note struct {
+	URL   string
+	Notes []string
+	Err   string
+}
+
+// NotesError is returned alongside entries of which the notes could not be
+// fetched, the entries themselves are fine.
+type NotesError struct {
+	// URLs of the notes that could not be fetched.
+	URLs []string
+	// Err is the error of the first failed fetch.
+	Err string
+}
+
+func (n NotesError) Error() string {
+	return fmt.Sprintf("failed to fetch %d notes: %s", len(n.URLs), n.Err)
+}
+
+func (c *Cache) notePath(u string) string {
+	sum := sha1.Sum([]byte(u))
+	return filepath.Join(c.dir, "notes", hex.EncodeToString(sum[:]))
+}
+
+// fetchNotes makes sure the notes of all entries are cached, fetching those
+// that are missing, expired or failed previously, or all of them if force is
+// true. Notes shared by several developers are only refetched once by c.
+// Failed fetches are recorded and only a cancelled ctx results in an error.
+func (c *Cache) fetchNotes(ctx context.Context, entries []Entry, force bool) error {
+	urls := make([]string, 0)
+	uniq := make(map[string]struct{})
+	for _, e := range entries {
+		if _, ok := uniq[e.NotesURL]; ok || e.NotesURL == "" {
+			continue
+		}
+		uniq[e.NotesURL] = struct{}{}
+		urls = append(urls, e.NotesURL)
+	}
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for w := 0; w < noteWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for u := range jobs {
+				c.fetchNote(ctx, u, force)
+			}
+		}()
+	}
+
+outer:
+	for _, u := range urls {
+		select {
+		case <-ctx.Done():
+			break outer
+		case jobs <- u:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+func (c *Cache) fetchNote(ctx context.Context, u string, force bool) {
+	l, _ := c.noteLocks.LoadOrStore(u, &sync.Mutex{})
+	mu := l.(*sync.Mutex)
+	mu.Lock()
+	defer mu.Unlock()
+
+	file := c.notePath(u)
+	var rec record[note]
+	cached := readRecord(file, &rec) == nil && rec.Data.Err == ""
+	_, refreshed := c.refreshedNotes.Load(u)
+	if cached && (refreshed || !force && !c.expired(rec.Fetched)) {
+		return
+	}
+
+	notes, err := c.client.Notes(ctx, u)
+	if ctx.Err() != nil {
+		return
+	}
+
+	n := note{URL: u, Notes: notes}
+	if err != nil {
+		n = note{URL: u, Notes: rec.Data.Notes, Err: err.Error()}
+	} else if force {
+		c.refreshedNotes.Store(u, struct{}{})
+	}
+
+	_ = writeRecord(file, record[note]{Version: cacheVersion, Fetched: time.Now(), Data: n})
+}
+
+// readNotes sets the notes of entries that have none from the note cache.
+// A NotesError is returned if the last fetch of any of the notes of entries
+// failed.
+func (c *Cache) readNotes(entries []Entry) error {
+	var nerr NotesError
+	notes := make(map[string]note)
+	for i, e := range entries {
+		if e.NotesURL == "" {
+			continue
+		}
+
+		n, ok := notes[e.NotesURL]
+		if !ok {
+			var rec record[note]
+			if readRecord(c.notePath(e.NotesURL), &rec) == nil {
+				n = rec.Data
+			}
+			notes[e.NotesURL] = n
+			if n.Err != "" {
+				nerr.URLs = append(nerr.URLs, e.NotesURL)
+				if nerr.Err == "" {
+					nerr.Err = n.Err
+				}
+			}
+		}
+		if e.Notes == nil {
+			entries[i].Notes = n.Notes
+		}
+	}
+
+	if len(nerr.URLs) != 0 {
+		return nerr
+	}
+
+	return nil
+}
@@ -0,0 +1,199 @@
+package devchart
+
+import (
+	"io"
+	"strings"
+
+	"github.com/frizinak/devcalc/dev"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+type column int
+
+const (
+	colFilm column = iota
+	colDeveloper
+	colDilution
+	colISO
+	col135
+	col120
+	colSheet
+	colTemp
+	colNotes
+	numColumns
+)
+
+// headers maps normalized header names to columns.
+var headers = map[string]column{
+	"film":        colFilm,
+	"developer":   colDeveloper,
+	"dilution":    colDilution,
+	"asa/iso":     colISO,
+	"iso":         colISO,
+	"asa":         colISO,
+	"35mm":        col135,
+	"135":         col135,
+	"120":         col120,
+	"sheet":       colSheet,
+	"temp":        colTemp,
+	"temperature": colTemp,
+	"notes":       colNotes,
+}
+
+// required columns for a table to be considered a results table.
+var required = []column{colFilm, colDeveloper, colDilution, colISO}
+
+type cell struct {
+	text string
+	href string
+}
+
+// Parse parses a Massive Dev Chart results page.
+// Columns are mapped by the names in the header row of the results table, a
+// LayoutError is returned if no such table exists.
+// Notes are not fetched, NotesURL is set to the href in the notes column
+// as is, i.e. it might be relative.
+func Parse(r io.Reader) ([]Entry, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, table := range findAll(doc, atom.Table) {
+		rows := tableRows(table)
+		for i, row := range rows {
+			cols, ok := header(row)
+			if !ok {
+				continue
+			}
+
+			return parseRows(cols, rows[i+1:]), nil
+		}
+	}
+
+	return nil, LayoutError{Reason: "no results table found"}
+}
+
+func header(row []cell) (cols [numColumns]int, ok bool) {
+	for i := range cols {
+		cols[i] = -1
+	}
+
+	for i, c := range row {
+		name := strings.ToLower(strings.ReplaceAll(c.text, " ", ""))
+		if col, ok := headers[name]; ok && cols[col] == -1 {
+			cols[col] = i
+		}
+	}
+
+	for _, col := range required {
+		if cols[col] == -1 {
+			return cols, false
+		}
+	}
+
+	return cols, true
+}
+
+func parseRows(cols [numColumns]int, rows [][]cell) []Entry {
+	entries := make([]Entry, 0, len(rows))
+	for _, row := range rows {
+		get := func(col column) cell {
+			i := cols[col]
+			if i == -1 || i >= len(row) {
+				return cell{}
+			}
+			return row[i]
+		}
+
+		if get(colFilm).text == "" || get(colDeveloper).text == "" {
+			continue
+		}
+
+		temp, err := dev.ParseTemp(get(colTemp).text, dev.Celsius)
+		if err != nil {
+			temp = 0
+		}
+
+		entries = append(entries, Entry{
+			Name:      get(colFilm).text,
+			Developer: get(colDeveloper).text,
+			Dilution:  get(colDilution).text,
+			ISO:       get(colISO).text,
+			T135:      ParseTime(get(col135).text),
+			T120:      ParseTime(get(col120).text),
+			TSheet:    ParseTime(get(colSheet).text),
+			Temp:      temp,
+			NotesURL:  get(colNotes).href,
+		})
+	}
+
+	return entries
+}
+
+// tableRows returns the cells of all rows that belong to table, excluding
+// those of nested tables.
+func tableRows(table *html.Node) [][]cell {
+	rows := make([][]cell, 0)
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			switch c.DataAtom {
+			case atom.Table:
+				continue
+			case atom.Tr:
+				row := make([]cell, 0, numColumns)
+				for td := c.FirstChild; td != nil; td = td.NextSibling {
+					if td.DataAtom == atom.Td || td.DataAtom == atom.Th {
+						row = append(row, parseCell(td))
+					}
+				}
+				rows = append(rows, row)
+			default:
+				walk(c)
+			}
+		}
+	}
+	walk(table)
+
+	return rows
+}
+
+func parseCell(n *html.Node) cell {
+	var c cell
+	var text strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			text.WriteString(n.Data)
+			text.WriteByte(' ')
+		case n.DataAtom == atom.A && c.href == "":
+			for _, a := range n.Attr {
+				if a.Key == "href" {
+					c.href = a.Val
+				}
+			}
+		}
+		for ch := n.FirstChild; ch != nil; ch = ch.NextSibling {
+			walk(ch)
+		}
+	}
+	walk(n)
+	c.text = strings.Join(strings.Fields(text.String()), " ")
+
+	return c
+}
+
+func findAll(n *html.Node, a atom.Atom) []*html.Node {
+	nodes := make([]*html.Node, 0)
+	if n.DataAtom == a {
+		nodes = append(nodes, n)
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		nodes = append(nodes, findAll(c, a)...)
+	}
+
+	return nodes
+}
@@ -0,0 +1,72 @@
+package devchart
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func parseFile(t *testing.T, name string) ([]Entry, error) {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	return Parse(f)
+}
+
+func TestParse(t *testing.T) {
+	entries, err := parseFile(t, "results.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Entry{
+		{
+			Name:      "Kentmere 400",
+			Developer: "Rodinal",
+			Dilution:  "1+25",
+			ISO:       "400",
+			T135:      ParseTime("8"),
+			T120:      ParseTime("7.5"),
+			Temp:      20,
+			NotesURL:  "/devchart.php?note=1",
+		},
+		{
+			Name:      "Ilford HP5+",
+			Developer: "Rodinal",
+			Dilution:  "1+50",
+			ISO:       "EI 800",
+			T135:      ParseTime("8-10"),
+			TSheet:    ParseTime("10½"),
+			Temp:      20,
+		},
+		{
+			Name:      "Ilford HP5+",
+			Developer: "Rodinal",
+			Dilution:  "1:25",
+			ISO:       "1600",
+			T135:      ParseTime("6+4"),
+		},
+	}
+
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(entries), len(want), entries)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(entries[i], want[i]) {
+			t.Errorf("entry %d:\n got %+v\nwant %+v", i, entries[i], want[i])
+		}
+	}
+}
+
+func TestParseMissingColumn(t *testing.T) {
+	_, err := parseFile(t, "missing_column.html")
+	if !errors.As(err, &LayoutError{}) {
+		t.Fatalf("got %v, want a LayoutError", err)
+	}
+}
@@ -0,0 +1,49 @@
+package devchart
+
+import (
+	"context"
+	"strings"
+)
+
+// SourceMDC is the Source of entries from the Massive Dev Chart.
+const SourceMDC = "mdc"
+
+// Source provides developers, stocks and their entries.
+// Developer and Film return a NotExistsError if the source has no entries for
+// the given name.
+type Source interface {
+	Developers(ctx context.Context) ([]string, error)
+	Stocks(ctx context.Context) ([]string, error)
+	Developer(ctx context.Context, dev string) ([]Entry, error)
+	Film(ctx context.Context, film string) ([]Entry, error)
+}
+
+var (
+	_ Source = &Cache{}
+	_ Source = Chart{}
+)
+
+// Developers returns the cached list of developers, see Options.
+func (c *Cache) Developers(ctx context.Context) ([]string, error) {
+	o, err := c.Options(ctx)
+	return o.Developers, err
+}
+
+// Stocks returns the cached list of stocks, see Options.
+func (c *Cache) Stocks(ctx context.Context) ([]string, error) {
+	o, err := c.Options(ctx)
+	return o.Stocks, err
+}
+
+func setSource(entries []Entry, source string) {
+	for i := range entries {
+		entries[i].Source = source
+	}
+}
+
+// sameName reports whether a and b refer to the same developer or stock,
+// ignoring case, spaces and dashes.
+func sameName(a, b string) bool {
+	r := strings.NewReplacer(" ", "", "-", "")
+	return strings.EqualFold(r.Replace(a), r.Replace(b))
+}
@@ -0,0 +1,130 @@
+package devchart
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var fractions = strings.NewReplacer(
+	"½", ".5",
+	"¼", ".25",
+	"¾", ".75",
+	"⅓", ".333",
+	"⅔", ".667",
+	"–", "-",
+	",", ".",
+	" ", "",
+)
+
+// Span is a duration range, Min equals Max for an exact duration.
+type Span struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+func (s Span) Add(o Span) Span { return Span{s.Min + o.Min, s.Max + o.Max} }
+
+// Time is a development time as listed in the Massive Dev Chart in decimal
+// minutes, e.g.: "7.5", "8-10" (a range), "6+4" (two-bath) or "10½".
+type Time struct {
+	// Raw is the time as it was listed.
+	Raw string
+	// Stages holds a Span for every bath, nil if Raw could not be parsed.
+	Stages []Span
+}
+
+// ParseTime parses a Massive Dev Chart time, unparsable times have no Stages.
+func ParseTime(raw string) Time {
+	t := Time{Raw: strings.TrimSpace(raw)}
+	if t.Raw == "" {
+		return t
+	}
+
+	parts := strings.Split(fractions.Replace(t.Raw), "+")
+	stages := make([]Span, 0, len(parts))
+	for _, p := range parts {
+		r := strings.SplitN(p, "-", 2)
+		min, ok := minutes(r[0])
+		if !ok {
+			return t
+		}
+		max := min
+		if len(r) == 2 {
+			if max, ok = minutes(r[1]); !ok || max < min {
+				return t
+			}
+		}
+		stages = append(stages, Span{min, max})
+	}
+	t.Stages = stages
+
+	return t
+}
+
+func minutes(str string) (time.Duration, bool) {
+	f, err := strconv.ParseFloat(str, 64)
+	if err != nil || f <= 0 {
+		return 0, false
+	}
+
+	return time.Duration(f * float64(time.Minute)).Round(time.Second), true
+}
+
+// IsZero reports whether t holds no usable time.
+func (t Time) IsZero() bool { return len(t.Stages) == 0 }
+
+// Total returns the combined duration of all stages.
+func (t Time) Total() Span {
+	var s Span
+	for _, st := range t.Stages {
+		s = s.Add(st)
+	}
+	return s
+}
+
+// Scale returns t with every stage multiplied by f, its Raw time is derived
+// from the scaled stages.
+func (t Time) Scale(f float64) Time {
+	if t.IsZero() {
+		return t
+	}
+
+	scale := func(d time.Duration) time.Duration {
+		return time.Duration(float64(d) * f).Round(time.Second)
+	}
+
+	stages := make([]Span, len(t.Stages))
+	for i, s := range t.Stages {
+		stages[i] = Span{scale(s.Min), scale(s.Max)}
+	}
+
+	return newTime(stages)
+}
+
+// newTime returns the Time of stages with Raw in decimal minutes.
+func newTime(stages []Span) Time {
+	decimal := func(d time.Duration) string {
+		return strconv.FormatFloat(math.Round(d.Minutes()*100)/100, 'f', -1, 64)
+	}
+
+	raw := make([]string, len(stages))
+	for i, s := range stages {
+		raw[i] = decimal(s.Min)
+		if s.Max != s.Min {
+			raw[i] += "-" + decimal(s.Max)
+		}
+	}
+
+	return Time{Raw: strings.Join(raw, "+"), Stages: stages}
+}
+
+func (t Time) String() string { return t.Raw }
+
+func (t Time) MarshalText() ([]byte, error) { return []byte(t.Raw), nil }
+
+func (t *Time) UnmarshalText(b []byte) error {
+	*t = ParseTime(string(b))
+	return nil
+}
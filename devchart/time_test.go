@@ -0,0 +1,59 @@
+package devchart
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	m := func(f float64) time.Duration { return time.Duration(f * float64(time.Minute)) }
+	tests := []struct {
+		raw    string
+		stages []Span
+	}{
+		{"", nil},
+		{"7", []Span{{m(7), m(7)}}},
+		{"7.5", []Span{{m(7.5), m(7.5)}}},
+		{"7,5", []Span{{m(7.5), m(7.5)}}},
+		{"10½", []Span{{m(10.5), m(10.5)}}},
+		{"6¼", []Span{{m(6.25), m(6.25)}}},
+		{"8-10", []Span{{m(8), m(10)}}},
+		{"8 – 10", []Span{{m(8), m(10)}}},
+		{"6+4", []Span{{m(6), m(6)}, {m(4), m(4)}}},
+		{"3-4+5", []Span{{m(3), m(4)}, {m(5), m(5)}}},
+		{"10-8", nil},
+		{"0", nil},
+		{"n/a", nil},
+		{"6+", nil},
+	}
+
+	for _, tt := range tests {
+		got := ParseTime(tt.raw)
+		if !reflect.DeepEqual(got.Stages, tt.stages) {
+			t.Errorf("ParseTime(%q) = %v, want %v", tt.raw, got.Stages, tt.stages)
+		}
+		if got.Raw != tt.raw {
+			t.Errorf("ParseTime(%q).Raw = %q", tt.raw, got.Raw)
+		}
+	}
+}
+
+func TestTimeScale(t *testing.T) {
+	tests := []struct {
+		raw  string
+		f    float64
+		want string
+	}{
+		{"10", 0.5, "5"},
+		{"8-10", 1.5, "12-15"},
+		{"6+4", 0.75, "4.5+3"},
+		{"", 2, ""},
+	}
+
+	for _, tt := range tests {
+		if got := ParseTime(tt.raw).Scale(tt.f).Raw; got != tt.want {
+			t.Errorf("ParseTime(%q).Scale(%v) = %q, want %q", tt.raw, tt.f, got, tt.want)
+		}
+	}
+}
@@ -63,6 +63,8 @@ func (f *Set) Usage(ex int) {
 
 func (f *Set) Args() []string { return f.f.Args() }
 
+func (f *Set) FlagSet() *flag.FlagSet { return f.f }
+
 func (f *Set) ParseCommandline() (sub *Set, trail []string) {
 	return f.Parse(os.Args[1:])
 }
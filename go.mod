@@ -3,7 +3,8 @@ module github.com/frizinak/devcalc
 go 1.22.1
 
 require (
-	github.com/containerd/console v1.0.4 // indirect
-	golang.org/x/net v0.24.0 // indirect
-	golang.org/x/sys v0.19.0 // indirect
+	github.com/containerd/console v1.0.4
+	golang.org/x/net v0.24.0
 )
+
+require golang.org/x/sys v0.19.0 // indirect